@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+
+	"go-architecture/internal/product/application"
+	productinfra "go-architecture/internal/product/infra"
+	"go-architecture/internal/shared/config"
+	"go-architecture/internal/shared/database"
+	"go-architecture/internal/shared/logger"
+	userapp "go-architecture/internal/user/application"
+	userinfra "go-architecture/internal/user/infra"
+	"go-architecture/internal/user/infra/hashing"
+	"go-architecture/migrations"
+)
+
+// loadConfig reads .env (if present) and the environment. Every command
+// starts here so they all see the same settings.
+func loadConfig(log *logger.Logger) (*config.Config, error) {
+	if err := godotenv.Load(); err != nil {
+		// not fatal — proceed, maybe env vars are set externally
+		log.Info(".env file not found or could not be loaded; relying on environment variables")
+	} else {
+		log.Info("Loaded .env file")
+	}
+
+	return config.LoadConfig()
+}
+
+// openDatabase connects to the configured backend.
+func openDatabase(cfg *config.Config, log *logger.Logger) (*sqlx.DB, error) {
+	return database.Open(cfg.Database, cfg.Server.Env == "development", log)
+}
+
+// autoMigrate brings the schema up to date in development when
+// DB_AUTO_MIGRATE is set. SQLite is the zero-setup local backend and is
+// always migrated.
+func autoMigrate(ctx context.Context, cfg *config.Config, db *sqlx.DB, log *logger.Logger) error {
+	if cfg.Database.AutoMigrate && cfg.Server.Env != "development" {
+		log.Warn("DB_AUTO_MIGRATE is ignored outside development; run `api migrate up`")
+	}
+	if !(cfg.Database.AutoMigrate && cfg.Server.Env == "development") && cfg.Database.Driver != database.DriverSQLite {
+		return nil
+	}
+
+	applied, err := database.Migrate(ctx, db, cfg.Database.Driver, migrations.FS)
+	if err != nil {
+		return err
+	}
+	log.Info("Database migrated", "applied", len(applied))
+	return nil
+}
+
+// services are the application services shared by the server and the
+// administrative commands.
+type services struct {
+	productRepos *productinfra.Repositories
+	userRepos    *userinfra.Repositories
+	products     *application.ProductService
+	reservations *application.ReservationService
+	users        *userapp.UserService
+	apiKeys      *userapp.APIKeyService
+}
+
+// newServices wires repositories and services for the configured backend.
+// Product changes, and API keys issued for other users, are checked with
+// authorizer.
+func newServices(cfg *config.Config, db *sqlx.DB, authorizer application.Authorizer) (*services, error) {
+	productRepos, err := productinfra.NewRepositories(cfg.Database.Driver, db)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepos, err := userinfra.NewRepositories(cfg.Database.Driver, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return &services{
+		productRepos: productRepos,
+		userRepos:    userRepos,
+		products:     application.NewProductService(productRepos.Products, productRepos.StockMovements, productRepos.UnitOfWork, authorizer),
+		reservations: application.NewReservationService(productRepos.StockReservations, productRepos.UnitOfWork, authorizer, cfg.Stock.ReservationTTL, cfg.Stock.MaxReservationTTL),
+		users:        userapp.NewUserService(userRepos.Users, userRepos.UnitOfWork, hashing.NewBcryptHasher(cfg.Auth.BcryptCost), cfg.JWT.AccessTokenTTL),
+		apiKeys:      userapp.NewAPIKeyService(userRepos.APIKeys, userRepos.Users, authorizer),
+	}, nil
+}
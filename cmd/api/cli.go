@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+
+	"go-architecture/internal/shared/config"
+	"go-architecture/internal/shared/logger"
+)
+
+// operator authorizes every change made from the command line: the
+// commands are run by someone who already has access to the database.
+type operator struct{}
+
+func (operator) Authorize(ctx context.Context, action, resource string) error {
+	return nil
+}
+
+// withDatabase loads the configuration, connects to the database and runs fn.
+// Logs go to stderr so that command output on stdout can be piped.
+func withDatabase(fn func(ctx context.Context, cfg *config.Config, db *sqlx.DB) error) error {
+	log := logger.New(os.Stderr)
+
+	cfg, err := loadConfig(log)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return fn(context.Background(), cfg, db)
+}
+
+// withServices is withDatabase plus the wired services, with the schema
+// migrated as on server start.
+func withServices(fn func(ctx context.Context, cfg *config.Config, svc *services) error) error {
+	return withDatabase(func(ctx context.Context, cfg *config.Config, db *sqlx.DB) error {
+		if err := autoMigrate(ctx, cfg, db, logger.New(os.Stderr)); err != nil {
+			return err
+		}
+
+		svc, err := newServices(cfg, db, operator{})
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, cfg, svc)
+	})
+}
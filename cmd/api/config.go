@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"go-architecture/internal/shared/database"
+	"go-architecture/internal/shared/logger"
+)
+
+const redacted = "*****"
+
+// configCommand prints the effective configuration, one Section.Field per
+// line, with secrets redacted.
+func configCommand(args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return usageError("config needs the print subcommand")
+	}
+
+	cfg, err := loadConfig(logger.New(os.Stderr))
+	if err != nil {
+		return err
+	}
+
+	shown := *cfg
+	shown.Database.DSN = database.RedactDSN(cfg.Database.Driver, cfg.Database.DSN)
+	shown.JWT.Secret = redact(cfg.JWT.Secret)
+	shown.Auth.AdminPassword = redact(cfg.Auth.AdminPassword)
+
+	sections := reflect.ValueOf(shown)
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			fmt.Printf("%s.%s = %v\n", sections.Type().Field(i).Name, section.Type().Field(j).Name, section.Field(j).Interface())
+		}
+	}
+	return nil
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"strconv"
+
+	"go-architecture/internal/product/application"
+	"go-architecture/internal/shared/config"
+)
+
+// exportPageSize is the largest page the product service hands out.
+const exportPageSize = 100
+
+// productCSVHeader is also understood by `seed`.
+var productCSVHeader = []string{"id", "name", "description", "price", "currency", "stock", "category", "active", "status", "created_at", "updated_at", "version"}
+
+// products exports the catalog or purges deleted products.
+func products(args []string) error {
+	if len(args) == 0 {
+		return usageError("products needs a subcommand")
+	}
+
+	switch args[0] {
+	case "export":
+		return exportProducts(args[1:])
+	case "purge":
+		return purgeProducts(args[1:])
+	}
+	return usageError("unknown products subcommand %q", args[0])
+}
+
+func exportProducts(args []string) error {
+	flags := flag.NewFlagSet("products export", flag.ContinueOnError)
+	format := flags.String("format", "csv", "output format: csv or json")
+	output := flags.String("o", "", "output file (default stdout)")
+	category := flags.String("category", "", "only export these comma-separated categories")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return usageError("unknown export format %q", *format)
+	}
+
+	return withServices(func(ctx context.Context, cfg *config.Config, svc *services) error {
+		var all []application.ProductResponseDTO
+		filters := application.ProductListFiltersDTO{Category: *category, Limit: exportPageSize}
+		for {
+			page, err := svc.products.GetAll(ctx, filters)
+			if err != nil {
+				return err
+			}
+			all = append(all, page.Products...)
+			if page.NextCursor == "" {
+				break
+			}
+			filters.Cursor = page.NextCursor
+		}
+
+		var w io.Writer = os.Stdout
+		if *output != "" {
+			f, err := os.Create(*output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if *format == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(all)
+		}
+		return writeProductCSV(w, all)
+	})
+}
+
+func writeProductCSV(w io.Writer, products []application.ProductResponseDTO) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(productCSVHeader); err != nil {
+		return err
+	}
+	for _, p := range products {
+		record := []string{
+			p.ID, p.Name, p.Description, p.Price, p.Currency, strconv.Itoa(p.Stock), p.Category,
+			strconv.FormatBool(p.Active), p.Status, p.CreatedAt, p.UpdatedAt, strconv.FormatInt(p.Version, 10),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"go-architecture/internal/shared/config"
+	"go-architecture/internal/shared/database"
+	"go-architecture/migrations"
+)
+
+// migrate applies, rolls back and lists the embedded migrations, and
+// scaffolds new ones.
+func migrate(args []string) error {
+	if len(args) == 0 {
+		return usageError("migrate needs a subcommand")
+	}
+	sub, args := args[0], args[1:]
+
+	flags := flag.NewFlagSet("migrate "+sub, flag.ContinueOnError)
+	steps := flags.Int("steps", 1, "number of migrations to roll back")
+	dir := flags.String("dir", "migrations", "migrations directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	switch sub {
+	case "create":
+		if flags.NArg() != 1 {
+			return usageError("migrate create needs exactly one NAME")
+		}
+		paths, err := database.CreateMigration(*dir, flags.Arg(0))
+		for _, path := range paths {
+			fmt.Println("created", path)
+		}
+		return err
+	case "up", "down", "status":
+	default:
+		return usageError("unknown migrate subcommand %q", sub)
+	}
+
+	return withDatabase(func(ctx context.Context, cfg *config.Config, db *sqlx.DB) error {
+		migrator, err := database.NewMigrator(db, cfg.Database.Driver, migrations.FS)
+		if err != nil {
+			return err
+		}
+
+		switch sub {
+		case "up":
+			applied, err := migrator.Up(ctx)
+			for _, m := range applied {
+				fmt.Printf("applied %s_%s\n", m.Version, m.Name)
+			}
+			if err == nil && len(applied) == 0 {
+				fmt.Println("no pending migrations")
+			}
+			return err
+		case "down":
+			rolledBack, err := migrator.Down(ctx, *steps)
+			for _, m := range rolledBack {
+				fmt.Printf("rolled back %s_%s\n", m.Version, m.Name)
+			}
+			return err
+		default:
+			return printMigrationStatus(ctx, migrator)
+		}
+	})
+}
+
+func printMigrationStatus(ctx context.Context, migrator *database.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for _, s := range statuses {
+		state := "pending"
+		if s.AppliedAt != nil {
+			state = "applied " + s.AppliedAt.Format(time.RFC3339)
+		}
+		if s.Modified {
+			state += " (modified since applied)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Version, s.Name, state)
+	}
+	return w.Flush()
+}
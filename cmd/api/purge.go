@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"go-architecture/internal/shared/config"
+)
+
+// defaultRetention is how long deleted products can still be restored.
+const defaultRetention = 30 * 24 * time.Hour
+
+// purgeProducts permanently removes the products deleted longer ago than the
+// retention period.
+func purgeProducts(args []string) error {
+	flags := flag.NewFlagSet("products purge", flag.ContinueOnError)
+	olderThan := flags.Duration("older-than", defaultRetention, "purge products deleted longer ago than this")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *olderThan < 0 {
+		return usageError("-older-than cannot be negative")
+	}
+
+	return withServices(func(ctx context.Context, cfg *config.Config, svc *services) error {
+		purged, err := svc.products.Purge(ctx, *olderThan)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("purged %d products deleted more than %s ago\n", purged, *olderThan)
+		return nil
+	})
+}
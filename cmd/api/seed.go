@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go-architecture/internal/product/application"
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/shared/config"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+// seed loads fixture products from a JSON array or a CSV file with a header
+// row. Products whose name already exists are skipped, so seeding twice is
+// harmless.
+func seed(args []string) error {
+	if len(args) != 1 {
+		return usageError("seed needs exactly one FILE")
+	}
+
+	fixtures, err := readProductFixtures(args[0])
+	if err != nil {
+		return err
+	}
+
+	return withServices(func(ctx context.Context, cfg *config.Config, svc *services) error {
+		created, skipped := 0, 0
+		for i, dto := range fixtures {
+			_, err := svc.products.Create(ctx, dto)
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, apperrors.ErrConflict):
+				skipped++
+			default:
+				return fmt.Errorf("product %d (%s): %w", i+1, dto.Name, err)
+			}
+		}
+
+		fmt.Printf("seeded %d products, %d already existed\n", created, skipped)
+		return nil
+	})
+}
+
+func readProductFixtures(path string) ([]application.CreateProductDTO, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var fixtures []application.CreateProductDTO
+		if err := json.NewDecoder(f).Decode(&fixtures); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return fixtures, nil
+	case ".csv":
+		return readProductCSV(f)
+	default:
+		return nil, fmt.Errorf("%s: unsupported fixture format, want .json or .csv", path)
+	}
+}
+
+// readProductCSV reads rows keyed by the header row. Columns are matched by
+// name (name, description, price, currency, stock, category) and unknown
+// columns are ignored, so files written by `products export` can be loaded.
+func readProductCSV(r io.Reader) ([]application.CreateProductDTO, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	column := make(map[string]int)
+	for i, name := range rows[0] {
+		column[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "price", "stock", "category"} {
+		if _, ok := column[required]; !ok {
+			return nil, fmt.Errorf("csv: missing %q column", required)
+		}
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := column[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	fixtures := make([]application.CreateProductDTO, 0, len(rows)-1)
+	for n, row := range rows[1:] {
+		stock, err := strconv.Atoi(field(row, "stock"))
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: invalid stock: %w", n+2, err)
+		}
+		// Products are created as drafts or active; exported products that
+		// have moved further along their lifecycle come back as drafts.
+		status := field(row, "status")
+		if status != string(domain.StatusActive) {
+			status = string(domain.StatusDraft)
+		}
+		fixtures = append(fixtures, application.CreateProductDTO{
+			Name:        field(row, "name"),
+			Description: field(row, "description"),
+			Price:       json.Number(field(row, "price")),
+			Currency:    field(row, "currency"),
+			Stock:       stock,
+			Category:    field(row, "category"),
+			Status:      status,
+		})
+	}
+	return fixtures, nil
+}
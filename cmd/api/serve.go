@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+
+	"go-architecture/internal/product/infra/http"
+	"go-architecture/internal/shared/auth"
+	"go-architecture/internal/shared/authz"
+	"go-architecture/internal/shared/config"
+	sharedhttp "go-architecture/internal/shared/http"
+	"go-architecture/internal/shared/logger"
+	"go-architecture/internal/shared/middleware"
+	userapp "go-architecture/internal/user/application"
+	userhttp "go-architecture/internal/user/infra/http"
+)
+
+// serve runs the HTTP API until SIGINT or SIGTERM.
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	port := flags.String("port", "", "listen port (default SERVER_PORT)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Initialize logger
+	log := logger.NewLogger()
+	log.Info("Starting application...")
+
+	// Load configuration
+	cfg, err := loadConfig(log)
+	if err != nil {
+		return err
+	}
+	if *port != "" {
+		cfg.Server.Port = *port
+	}
+
+	// Load token signing keys
+	keys, err := initKeys(cfg)
+	if err != nil {
+		log.Fatal("Failed to load JWT keys", "error", err)
+	}
+
+	// Initialize database
+	db, err := openDatabase(cfg, log)
+	if err != nil {
+		log.Fatal("Failed to connect to database", "error", err)
+	}
+	defer db.Close()
+
+	if err := autoMigrate(context.Background(), cfg, db, log); err != nil {
+		log.Fatal("Failed to migrate database", "error", err)
+	}
+
+	// Initialize Fiber app
+	app := fiber.New(fiber.Config{
+		ErrorHandler: middleware.ErrorHandler,
+		AppName:      "Go Architecture API",
+	})
+
+	// Global middleware
+	app.Use(recover.New())
+	app.Use(cors.New(cors.Config{
+		AllowOrigins:     cfg.CORS.AllowedOrigins,
+		AllowMethods:     "GET,POST,PUT,DELETE,PATCH",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-API-Key,If-Match,If-None-Match",
+		ExposeHeaders:    "ETag,Link",
+		AllowCredentials: true,
+	}))
+	app.Use(middleware.RequestLogger(log))
+	app.Use(middleware.RateLimiter())
+
+	// Health check endpoint
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"status": "ok",
+			"time":   time.Now(),
+		})
+	})
+
+	// Role -> permission policy
+	policy := authz.NewPolicy(cfg.Authz.RolePermissions)
+
+	// Initialize dependencies
+	svc, err := newServices(cfg, db, policy)
+	if err != nil {
+		log.Fatal("Failed to initialize repositories", "error", err)
+	}
+	productHandler := http.NewProductHandler(svc.products, log)
+	reservationHandler := http.NewReservationHandler(svc.reservations, log)
+	userService := svc.users
+	userHandler := userhttp.NewUserHandler(userService, log)
+	tokenIssuer := auth.NewTokenIssuer(keys, auth.IssuerOptions{
+		Issuer:   cfg.JWT.Issuer,
+		Audience: cfg.JWT.Audience,
+		TTL:      cfg.JWT.AccessTokenTTL,
+	})
+	tokenVerifier := auth.NewVerifier(keys, auth.VerifierOptions{
+		Issuer:     cfg.JWT.Issuer,
+		Audience:   cfg.JWT.Audience,
+		Algorithms: cfg.JWT.AllowedAlgorithms,
+		Leeway:     cfg.JWT.Leeway,
+	})
+	sessionService := userapp.NewSessionService(
+		userService,
+		svc.userRepos.UnitOfWork,
+		svc.userRepos.RevokedTokens,
+		tokenIssuer,
+		cfg.JWT.RefreshTokenTTL,
+	)
+	authHandler := userhttp.NewAuthHandler(sessionService, log)
+	apiKeyService := svc.apiKeys
+	apiKeyHandler := userhttp.NewAPIKeyHandler(apiKeyService, log)
+
+	if cfg.Auth.AdminUsername != "" {
+		if err := userService.EnsureAdmin(context.Background(), cfg.Auth.AdminUsername, cfg.Auth.AdminEmail, cfg.Auth.AdminPassword); err != nil {
+			log.Fatal("Failed to bootstrap admin user", "error", err)
+		}
+	}
+
+	// Public token verification keys
+	app.Get("/.well-known/jwks.json", sharedhttp.JWKSHandler(keys))
+
+	// API routes
+	api := app.Group("/api/v1")
+
+	requireAuth := middleware.JWTProtected(tokenVerifier, sessionService)
+	// Routes used by machine clients also accept an X-API-Key
+	requireClient := middleware.JWTOrAPIKey(requireAuth, middleware.APIKeyProtected(apiKeyService))
+
+	// Auth
+	api.Post("/login", authHandler.Login)
+	api.Post("/token/refresh", authHandler.Refresh)
+	api.Post("/logout", requireAuth, authHandler.Logout)
+	api.Post("/logout-all", requireAuth, authHandler.LogoutAll)
+	api.Post("/register", userHandler.Register)
+
+	can := func(resource, action string) fiber.Handler {
+		return middleware.RequirePermission(policy, resource, action)
+	}
+
+	// User routes: self-service first so "/me" is not captured by "/:id"
+	users := api.Group("/users")
+	users.Get("/me", requireAuth, userHandler.Me)
+	users.Put("/me/password", requireAuth, userHandler.ChangePassword)
+	users.Get("/", requireAuth, can(authz.ResourceUser, authz.ActionRead), userHandler.GetAll)
+	users.Post("/", requireAuth, can(authz.ResourceUser, authz.ActionWrite), userHandler.Create)
+	users.Get("/:id", requireAuth, can(authz.ResourceUser, authz.ActionRead), userHandler.GetByID)
+	users.Put("/:id", requireAuth, can(authz.ResourceUser, authz.ActionWrite), userHandler.Update)
+	users.Delete("/:id", requireAuth, can(authz.ResourceUser, authz.ActionDelete), userHandler.Delete)
+
+	// API keys: managed by users with a session, never by other keys
+	apiKeys := api.Group("/api-keys")
+	apiKeys.Get("/", requireAuth, can(authz.ResourceAPIKey, authz.ActionRead), apiKeyHandler.GetAll)
+	apiKeys.Post("/", requireAuth, can(authz.ResourceAPIKey, authz.ActionWrite), apiKeyHandler.Create)
+	apiKeys.Put("/:id/expiry", requireAuth, can(authz.ResourceAPIKey, authz.ActionWrite), apiKeyHandler.SetExpiry)
+	apiKeys.Delete("/:id", requireAuth, can(authz.ResourceAPIKey, authz.ActionDelete), apiKeyHandler.Revoke)
+
+	// Product routes: reads other than the stock ledger are public, changes
+	// need a permission. Listing deleted products needs a client, whose
+	// permission the service checks.
+	includingDeleted := func(c *fiber.Ctx) error {
+		if c.QueryBool("include_deleted") {
+			return requireClient(c)
+		}
+		return c.Next()
+	}
+	products := api.Group("/products")
+	products.Get("/", includingDeleted, productHandler.GetAll)
+	products.Get("/search", productHandler.Search)
+	products.Get("/:id", productHandler.GetByID)
+	products.Get("/:id/stock/movements", requireClient, can(authz.ResourceProduct, authz.ActionRead), productHandler.StockHistory)
+	products.Post("/", requireClient, can(authz.ResourceProduct, authz.ActionWrite), productHandler.Create)
+	products.Put("/:id", requireClient, can(authz.ResourceProduct, authz.ActionWrite), productHandler.Update)
+	products.Patch("/:id", requireClient, can(authz.ResourceProduct, authz.ActionWrite), productHandler.Patch)
+	products.Post("/:id/transitions", requireClient, can(authz.ResourceProduct, authz.ActionWrite), productHandler.Transition)
+	products.Post("/:id/activate", requireClient, can(authz.ResourceProduct, authz.ActionWrite), productHandler.Activate)
+	products.Post("/:id/deactivate", requireClient, can(authz.ResourceProduct, authz.ActionWrite), productHandler.Deactivate)
+	products.Post("/:id/stock/adjustments", requireClient, can(authz.ResourceProduct, authz.ActionWrite), productHandler.AdjustStock)
+	products.Delete("/:id", requireClient, can(authz.ResourceProduct, authz.ActionDelete), productHandler.Delete)
+	products.Post("/:id/restore", requireClient, can(authz.ResourceProduct, authz.ActionDelete), productHandler.Restore)
+
+	// Stock reservations
+	products.Post("/:id/reservations", requireClient, can(authz.ResourceProduct, authz.ActionWrite), reservationHandler.Reserve)
+	products.Get("/:id/reservations/:reservation_id", requireClient, can(authz.ResourceProduct, authz.ActionRead), reservationHandler.Get)
+	products.Post("/:id/reservations/:reservation_id/confirm", requireClient, can(authz.ResourceProduct, authz.ActionWrite), reservationHandler.Confirm)
+	products.Post("/:id/reservations/:reservation_id/release", requireClient, can(authz.ResourceProduct, authz.ActionWrite), reservationHandler.Release)
+
+	// Release expired reservations in the background
+	sweepCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	if cfg.Stock.SweepInterval > 0 {
+		go sweepReservations(sweepCtx, svc.reservations, cfg.Stock.SweepInterval, log)
+	}
+
+	// Graceful shutdown
+	go func() {
+		if err := app.Listen(":" + cfg.Server.Port); err != nil {
+			log.Fatal("Server error", "error", err)
+		}
+	}()
+
+	log.Info("Server started on port " + cfg.Server.Port)
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Info("Shutting down server...")
+	stopSweeper()
+
+	if err := app.ShutdownWithContext(context.Background()); err != nil {
+		log.Error("Server forced to shutdown", "error", err)
+	}
+
+	log.Info("Server stopped")
+	return nil
+}
+
+// initKeys loads the asymmetric signing key when one is configured and falls
+// back to the shared HMAC secret otherwise.
+func initKeys(cfg *config.Config) (*auth.KeySet, error) {
+	if cfg.JWT.SigningKeyFile == "" {
+		return auth.NewHMACKeySet(cfg.JWT.Secret), nil
+	}
+	return auth.LoadKeySet(cfg.JWT.SigningKeyFile, cfg.JWT.VerificationKeyFiles)
+}
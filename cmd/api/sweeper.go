@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go-architecture/internal/product/application"
+	"go-architecture/internal/shared/logger"
+)
+
+// sweepReservations releases expired stock reservations every interval until
+// ctx is done.
+func sweepReservations(ctx context.Context, reservations *application.ReservationService, interval time.Duration, log *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := reservations.ReleaseExpired(ctx)
+			if err != nil {
+				log.Error("Failed to release expired reservations", "error", err)
+			}
+			if released > 0 {
+				log.Info("Released expired reservations", "count", released)
+			}
+		}
+	}
+}
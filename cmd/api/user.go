@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-architecture/internal/shared/config"
+	userapp "go-architecture/internal/user/application"
+)
+
+// user creates accounts and changes their roles.
+func user(args []string) error {
+	if len(args) == 0 {
+		return usageError("user needs a subcommand")
+	}
+	sub, args := args[0], args[1:]
+
+	flags := flag.NewFlagSet("user "+sub, flag.ContinueOnError)
+	username := flags.String("username", "", "username")
+	email := flags.String("email", "", "email address")
+	password := flags.String("password", "", "password; read from stdin when empty")
+	roles := flags.String("roles", "", "comma-separated roles; create defaults to user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return usageError("user %s needs -username", sub)
+	}
+
+	switch sub {
+	case "create":
+		if *email == "" {
+			return usageError("user create needs -email")
+		}
+		if *roles == "" {
+			*roles = "user"
+		}
+		if *password == "" {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("read password from stdin: %w", err)
+			}
+			*password = strings.TrimRight(line, "\r\n")
+		}
+
+		return withServices(func(ctx context.Context, cfg *config.Config, svc *services) error {
+			created, err := svc.users.Create(ctx, userapp.CreateUserDTO{
+				Username: *username,
+				Email:    *email,
+				Password: *password,
+				Roles:    splitList(*roles),
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("created user %s (%s) with roles %s\n", created.Username, created.ID, strings.Join(created.Roles, ","))
+			return nil
+		})
+	case "set-role":
+		// Without an explicit list, set-role would silently demote the user
+		if len(splitList(*roles)) == 0 {
+			return usageError("user set-role needs -roles")
+		}
+		return withServices(func(ctx context.Context, cfg *config.Config, svc *services) error {
+			found, err := svc.users.GetByUsername(ctx, *username)
+			if err != nil {
+				return err
+			}
+			updated, err := svc.users.Update(ctx, found.ID, userapp.UpdateUserDTO{Roles: splitList(*roles)})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("user %s now has roles %s\n", updated.Username, strings.Join(updated.Roles, ","))
+			return nil
+		})
+	default:
+		return usageError("unknown user subcommand %q", sub)
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"time"
+
+	"go-architecture/internal/product/domain"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+// cursorToken is the payload of a cursor: the position in the listing and
+// the sort order it is a position in.
+type cursorToken struct {
+	Sort string               `json:"sort"`
+	Key  domain.ProductCursor `json:"key"`
+}
+
+// EncodeCursor turns a listing position into the opaque token returned as
+// next_cursor. Clients must not rely on its format.
+func EncodeCursor(order []domain.ProductSort, cursor domain.ProductCursor) string {
+	raw, _ := json.Marshal(cursorToken{Sort: FormatSort(order), Key: cursor})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor for the same sort
+// order; a cursor is meaningless in any other order.
+func DecodeCursor(order []domain.ProductSort, token string) (*domain.ProductCursor, error) {
+	invalid := apperrors.NewValidationError("Invalid cursor", map[string]interface{}{"cursor": token})
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var decoded cursorToken
+	if err := json.Unmarshal(raw, &decoded); err != nil || decoded.Key.ID == "" {
+		return nil, invalid
+	}
+
+	if decoded.Sort != FormatSort(order) {
+		return nil, apperrors.NewValidationError("Cursor was issued for a different sort", map[string]interface{}{"sort": decoded.Sort})
+	}
+
+	if !validCursor(order, decoded.Key) {
+		return nil, invalid
+	}
+
+	return &decoded.Key, nil
+}
+
+// validCursor checks the values the keyset compares with the sort columns,
+// so that a tampered cursor is rejected here rather than by the database.
+func validCursor(order []domain.ProductSort, key domain.ProductCursor) bool {
+	for _, s := range order {
+		switch s.Field {
+		case domain.SortByPrice:
+			// Every supported currency has at most two minor units, like the
+			// price columns.
+			price, err := domain.ParsePrice(key.Price, domain.DefaultCurrency)
+			if err != nil || price.IsNegative() {
+				return false
+			}
+		case domain.SortByStock:
+			if key.Stock < 0 || key.Stock > math.MaxInt32 {
+				return false
+			}
+		case domain.SortByUpdatedAt:
+			if !validCursorTime(key.UpdatedAt) {
+				return false
+			}
+		case domain.SortByCreatedAt:
+			if !validCursorTime(key.CreatedAt) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validCursorTime reports whether t is within the years every backend can
+// store.
+func validCursorTime(t time.Time) bool {
+	year := t.UTC().Year()
+	return year >= 1 && year <= 9999
+}
@@ -1,36 +1,203 @@
 package application
 
+import "encoding/json"
+
 type CreateProductDTO struct {
-	Name        string  `json:"name" validate:"required,min=3,max=100"`
-	Description string  `json:"description" validate:"max=500"`
-	Price       float64 `json:"price" validate:"required,gt=0"`
-	Stock       int     `json:"stock" validate:"required,gte=0"`
-	Category    string  `json:"category" validate:"required,min=3,max=50"`
+	Name        string      `json:"name" validate:"required,min=3,max=100"`
+	Description string      `json:"description" validate:"max=500"`
+	Price       json.Number `json:"price" validate:"required"`
+	Currency    string      `json:"currency" validate:"omitempty,len=3,alpha"`
+	Stock       int         `json:"stock" validate:"required,gte=0"`
+	Category    string      `json:"category" validate:"required,min=3,max=50"`
+	// Status is the initial lifecycle status, draft unless set to active.
+	Status string `json:"status" validate:"omitempty,oneof=draft active"`
 }
 
 type UpdateProductDTO struct {
-	Name        string  `json:"name" validate:"required,min=3,max=100"`
-	Description string  `json:"description" validate:"max=500"`
-	Price       float64 `json:"price" validate:"required,gt=0"`
-	Stock       int     `json:"stock" validate:"required,gte=0"`
-	Category    string  `json:"category" validate:"required,min=3,max=50"`
+	Name        string      `json:"name" validate:"required,min=3,max=100"`
+	Description string      `json:"description" validate:"max=500"`
+	Price       json.Number `json:"price" validate:"required"`
+	Currency    string      `json:"currency" validate:"omitempty,len=3,alpha"`
+	Stock       int         `json:"stock" validate:"required,gte=0"`
+	Category    string      `json:"category" validate:"required,min=3,max=50"`
+}
+
+// ProductPatchDTO is the document a PATCH applies to: the writable fields of
+// a product, as GET returns them. Only the description may be removed.
+type ProductPatchDTO struct {
+	Name        *string      `json:"name" validate:"required,min=3,max=100"`
+	Description *string      `json:"description,omitempty" validate:"omitempty,max=500"`
+	Price       *json.Number `json:"price" validate:"required"`
+	Currency    *string      `json:"currency" validate:"required,len=3,alpha"`
+	Stock       *int         `json:"stock" validate:"required,gte=0"`
+	Category    *string      `json:"category" validate:"required,min=3,max=50"`
+}
+
+// TransitionProductDTO moves a product to another lifecycle status.
+type TransitionProductDTO struct {
+	Status string `json:"status" validate:"required"`
 }
 
 type ProductResponseDTO struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
-	Category    string  `json:"category"`
-	Active      bool    `json:"active"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       string `json:"price"`
+	Currency    string `json:"currency"`
+	Stock       int    `json:"stock"`
+	// Reserved is held by pending reservations; the rest is Available.
+	Reserved  int    `json:"reserved"`
+	Available int    `json:"available"`
+	Category  string `json:"category"`
+	Active    bool   `json:"active"`
+	Status    string `json:"status"`
+	// AllowedTransitions lists the statuses the product can move to next.
+	AllowedTransitions []string `json:"allowed_transitions"`
+	CreatedAt          string   `json:"created_at"`
+	UpdatedAt          string   `json:"updated_at"`
+	CreatedBy          string   `json:"created_by,omitempty"`
+	UpdatedBy          string   `json:"updated_by,omitempty"`
+	Version            int64    `json:"version"`
+	// DeletedAt and DeletedBy are only set on soft-deleted products.
+	DeletedAt string `json:"deleted_at,omitempty"`
+	DeletedBy string `json:"deleted_by,omitempty"`
 }
 
 type ProductListFiltersDTO struct {
+	// Category is a comma-separated list; a product matches any of them.
 	Category string `query:"category"`
+	Currency string `query:"currency" validate:"omitempty,len=3,alpha"`
 	Active   *bool  `query:"active"`
-	Limit    int    `query:"limit" validate:"max=100"`
-	Offset   int    `query:"offset" validate:"gte=0"`
+	// Status is a comma-separated list of lifecycle statuses.
+	Status string `query:"status"`
+	// MinPrice and MaxPrice are inclusive decimal amounts.
+	MinPrice string `query:"min_price"`
+	MaxPrice string `query:"max_price"`
+	MinStock *int   `query:"min_stock" validate:"omitempty,gte=0"`
+	MaxStock *int   `query:"max_stock" validate:"omitempty,gte=0"`
+	// InStock selects products with stock left (true) or sold out (false).
+	InStock *bool `query:"in_stock"`
+	// The date bounds are inclusive and accept RFC 3339 timestamps or
+	// YYYY-MM-DD dates; a date as an upper bound covers the whole day.
+	CreatedFrom string `query:"created_from"`
+	CreatedTo   string `query:"created_to"`
+	UpdatedFrom string `query:"updated_from"`
+	UpdatedTo   string `query:"updated_to"`
+	// NamePrefix and NameContains match the name case-insensitively.
+	NamePrefix   string `query:"name_prefix" validate:"max=100"`
+	NameContains string `query:"name_contains" validate:"max=100"`
+	// IncludeDeleted also lists soft-deleted products; it needs the
+	// product:delete permission.
+	IncludeDeleted bool `query:"include_deleted"`
+	// Sort is a comma-separated list of fields, each optionally prefixed
+	// with "-" for descending order, e.g. "price,-created_at".
+	Sort string `query:"sort"`
+	// Cursor continues the listing after the page that returned it as
+	// next_cursor. It replaces Offset and cannot be combined with it.
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"min=0,max=100"`
+	Offset int    `query:"offset" validate:"gte=0"`
+}
+
+// ProductPageDTO is one page of a product listing. Total counts every
+// product matching the filters; NextCursor is empty on the last page.
+type ProductPageDTO struct {
+	Products   []ProductResponseDTO
+	Total      int
+	Limit      int
+	Offset     int
+	HasMore    bool
+	NextCursor string
+}
+
+type ProductSearchDTO struct {
+	// Q is the search query; every word must start a word of the name or
+	// description.
+	Q      string `query:"q" validate:"required,max=200"`
+	Limit  int    `query:"limit" validate:"min=0,max=100"`
+	Offset int    `query:"offset" validate:"gte=0"`
+}
+
+// ProductSearchResultDTO is a product found by a search, with its rank and
+// its name and description as HTML with the matching words in <mark> tags.
+type ProductSearchResultDTO struct {
+	ProductResponseDTO
+	Rank       float64              `json:"rank"`
+	Highlights ProductHighlightsDTO `json:"highlights"`
+}
+
+type ProductHighlightsDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ProductSearchPageDTO is one page of search results, best match first.
+type ProductSearchPageDTO struct {
+	Results []ProductSearchResultDTO
+	Total   int
+	Limit   int
+	Offset  int
+	HasMore bool
+}
+
+// StockAdjustmentDTO changes the stock of a product. Quantity is signed:
+// negative for sales and damage, positive for restocks, either for
+// corrections.
+type StockAdjustmentDTO struct {
+	Reason   string `json:"reason" validate:"required,oneof=sale restock damage correction"`
+	Quantity int    `json:"quantity" validate:"required"`
+	Note     string `json:"note" validate:"max=200"`
+}
+
+type StockMovementDTO struct {
+	ID         string `json:"id"`
+	ProductID  string `json:"product_id"`
+	Quantity   int    `json:"quantity"`
+	Reason     string `json:"reason"`
+	StockAfter int    `json:"stock_after"`
+	Note       string `json:"note,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	CreatedBy  string `json:"created_by,omitempty"`
+}
+
+// StockAdjustmentResultDTO is the movement an adjustment recorded and the
+// product it left behind.
+type StockAdjustmentResultDTO struct {
+	Movement StockMovementDTO   `json:"movement"`
+	Product  ProductResponseDTO `json:"product"`
+}
+
+type StockHistoryFiltersDTO struct {
+	Limit  int `query:"limit" validate:"min=0,max=100"`
+	Offset int `query:"offset" validate:"gte=0"`
+}
+
+// StockHistoryPageDTO is one page of a product's movements, newest first.
+// LedgerBalance adds up every movement and should equal Stock.
+type StockHistoryPageDTO struct {
+	Movements     []StockMovementDTO
+	Total         int
+	Limit         int
+	Offset        int
+	HasMore       bool
+	Stock         int
+	LedgerBalance int
+}
+
+// ReserveStockDTO holds units of a product. TTL is a duration such as "10m";
+// it defaults to RESERVATION_TTL.
+type ReserveStockDTO struct {
+	Quantity int    `json:"quantity" validate:"required,gt=0"`
+	TTL      string `json:"ttl"`
+}
+
+type StockReservationDTO struct {
+	ID         string `json:"id"`
+	ProductID  string `json:"product_id"`
+	Quantity   int    `json:"quantity"`
+	Status     string `json:"status"`
+	ExpiresAt  string `json:"expires_at"`
+	CreatedAt  string `json:"created_at"`
+	CreatedBy  string `json:"created_by,omitempty"`
+	ResolvedAt string `json:"resolved_at,omitempty"`
 }
@@ -0,0 +1,169 @@
+package application
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"go-architecture/internal/product/domain"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+// sortFields whitelists the fields accepted by the sort parameter.
+var sortFields = map[string]domain.ProductSortField{
+	"name":       domain.SortByName,
+	"price":      domain.SortByPrice,
+	"stock":      domain.SortByStock,
+	"created_at": domain.SortByCreatedAt,
+	"updated_at": domain.SortByUpdatedAt,
+}
+
+// amountPattern matches the non-negative decimal amounts accepted as price
+// bounds.
+var amountPattern = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// ParseSort parses a sort parameter such as "price,-created_at". An empty
+// parameter yields the default order.
+func ParseSort(param string) ([]domain.ProductSort, error) {
+	if strings.TrimSpace(param) == "" {
+		return domain.DefaultProductSort, nil
+	}
+
+	var order []domain.ProductSort
+	seen := make(map[domain.ProductSortField]bool)
+	for _, term := range strings.Split(param, ",") {
+		term = strings.TrimSpace(term)
+		name := strings.TrimPrefix(term, "-")
+
+		field, ok := sortFields[name]
+		if !ok {
+			return nil, apperrors.NewValidationError("Invalid sort field", map[string]interface{}{"sort": term})
+		}
+		if seen[field] {
+			return nil, apperrors.NewValidationError("Duplicate sort field", map[string]interface{}{"sort": name})
+		}
+		seen[field] = true
+
+		order = append(order, domain.ProductSort{Field: field, Desc: strings.HasPrefix(term, "-")})
+	}
+
+	return order, nil
+}
+
+// FormatSort is the inverse of ParseSort.
+func FormatSort(order []domain.ProductSort) string {
+	terms := make([]string, len(order))
+	for i, s := range order {
+		terms[i] = string(s.Field)
+		if s.Desc {
+			terms[i] = "-" + terms[i]
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+// toFilters validates the listing parameters other than the cursor and
+// converts them to repository filters.
+func toFilters(dto ProductListFiltersDTO) (domain.ProductFilters, error) {
+	filters := domain.ProductFilters{
+		Currency:       strings.ToUpper(dto.Currency),
+		Active:         dto.Active,
+		MinStock:       dto.MinStock,
+		MaxStock:       dto.MaxStock,
+		NamePrefix:     dto.NamePrefix,
+		NameContains:   dto.NameContains,
+		IncludeDeleted: dto.IncludeDeleted,
+		Limit:          dto.Limit,
+		Offset:         dto.Offset,
+	}
+
+	for _, category := range strings.Split(dto.Category, ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			filters.Categories = append(filters.Categories, category)
+		}
+	}
+
+	for _, name := range strings.Split(dto.Status, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		status, err := domain.ParseProductStatus(name)
+		if err != nil {
+			return filters, apperrors.NewValidationError(err.Error(), map[string]interface{}{"status": name})
+		}
+		filters.Statuses = append(filters.Statuses, status)
+	}
+
+	for _, bound := range []struct {
+		param, value string
+		dst          *string
+	}{
+		{"min_price", dto.MinPrice, &filters.MinPrice},
+		{"max_price", dto.MaxPrice, &filters.MaxPrice},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		if !amountPattern.MatchString(bound.value) {
+			return filters, apperrors.NewValidationError("Invalid "+bound.param, map[string]interface{}{bound.param: bound.value})
+		}
+		*bound.dst = bound.value
+	}
+
+	if dto.InStock != nil {
+		if *dto.InStock {
+			if filters.MinStock == nil || *filters.MinStock < 1 {
+				one := 1
+				filters.MinStock = &one
+			}
+		} else {
+			zero := 0
+			filters.MaxStock = &zero
+		}
+	}
+
+	for _, bound := range []struct {
+		param, value string
+		endOfDay     bool
+		dst          **time.Time
+	}{
+		{"created_from", dto.CreatedFrom, false, &filters.CreatedFrom},
+		{"created_to", dto.CreatedTo, true, &filters.CreatedTo},
+		{"updated_from", dto.UpdatedFrom, false, &filters.UpdatedFrom},
+		{"updated_to", dto.UpdatedTo, true, &filters.UpdatedTo},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := parseDateBound(bound.value, bound.endOfDay)
+		if err != nil {
+			return filters, apperrors.NewValidationError("Invalid "+bound.param, map[string]interface{}{bound.param: bound.value})
+		}
+		*bound.dst = &t
+	}
+
+	sort, err := ParseSort(dto.Sort)
+	if err != nil {
+		return filters, err
+	}
+	filters.Sort = sort
+
+	return filters, nil
+}
+
+// parseDateBound accepts an RFC 3339 timestamp or a YYYY-MM-DD date, taken
+// as UTC. With endOfDay a date means its last instant.
+func parseDateBound(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
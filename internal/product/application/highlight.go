@@ -0,0 +1,97 @@
+package application
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// snippetLength is the approximate number of characters of a description
+// shown around its first match.
+const snippetLength = 160
+
+// highlight HTML-escapes text and wraps every word starting with one of terms
+// in <mark> tags. With maxLength > 0, text longer than that is cut to a
+// window around the first match, marked with ellipses.
+func highlight(text string, terms []string, maxLength int) string {
+	type word struct{ start, end int }
+
+	var words []word
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsNumber(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			words = append(words, word{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{start, len(text)})
+	}
+
+	var marked []word
+	for _, w := range words {
+		lower := strings.ToLower(text[w.start:w.end])
+		for _, term := range terms {
+			if strings.HasPrefix(lower, term) {
+				marked = append(marked, w)
+				break
+			}
+		}
+	}
+
+	from, to := 0, len(text)
+	if maxLength > 0 && utf8.RuneCountInString(text) > maxLength {
+		// Start a little before the first match, on a word boundary.
+		if len(marked) > 0 {
+			from = marked[0].start
+			for back := maxLength / 4; back > 0 && from > 0; back-- {
+				_, size := utf8.DecodeLastRuneInString(text[:from])
+				from -= size
+			}
+			for _, w := range words {
+				if w.end > from {
+					if w.start < from {
+						from = w.start
+					}
+					break
+				}
+			}
+		}
+		to = from
+		for n := 0; n < maxLength && to < len(text); n++ {
+			_, size := utf8.DecodeRuneInString(text[to:])
+			to += size
+		}
+		for _, w := range words {
+			if w.start < to && w.end > to {
+				to = w.start
+				break
+			}
+		}
+	}
+
+	var sb strings.Builder
+	if from > 0 {
+		sb.WriteString("…")
+	}
+	pos := from
+	for _, w := range marked {
+		if w.start < from || w.end > to {
+			continue
+		}
+		sb.WriteString(html.EscapeString(text[pos:w.start]))
+		sb.WriteString("<mark>" + html.EscapeString(text[w.start:w.end]) + "</mark>")
+		pos = w.end
+	}
+	sb.WriteString(html.EscapeString(strings.TrimRightFunc(text[pos:to], unicode.IsSpace)))
+	if to < len(text) {
+		sb.WriteString("…")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,112 @@
+package application_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"go-architecture/internal/product/application"
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/product/infra/memory"
+	"go-architecture/internal/shared/auth"
+	"go-architecture/internal/shared/authz"
+)
+
+func TestGetAllRejectsNegativeLimit(t *testing.T) {
+	service, repo := newService(t)
+	storeLegacyProduct(t, repo, "Television")
+
+	_, err := service.GetAll(context.Background(), application.ProductListFiltersDTO{Limit: -1})
+	appErr := assertAppError(t, err, 400)
+	if appErr.Details["Limit"] == nil {
+		t.Fatalf("details %v, want Limit", appErr.Details)
+	}
+}
+
+func TestSearchRejectsNegativeLimit(t *testing.T) {
+	service, repo := newService(t)
+	storeLegacyProduct(t, repo, "Television")
+
+	_, err := service.Search(context.Background(), application.ProductSearchDTO{Q: "television", Limit: -1})
+	appErr := assertAppError(t, err, 400)
+	if appErr.Details["Limit"] == nil {
+		t.Fatalf("details %v, want Limit", appErr.Details)
+	}
+}
+
+func TestGetAllCursor(t *testing.T) {
+	service, repo := newService(t)
+	first := storeLegacyProduct(t, repo, "Television")
+	storeLegacyProduct(t, repo, "Radio")
+
+	byPrice := []domain.ProductSort{{Field: domain.SortByPrice}}
+	byStock := []domain.ProductSort{{Field: domain.SortByStock, Desc: true}}
+	key := domain.CursorOf(first)
+	with := func(change func(*domain.ProductCursor)) domain.ProductCursor {
+		c := key
+		change(&c)
+		return c
+	}
+
+	cases := []struct {
+		name   string
+		sort   string
+		cursor string
+		valid  bool
+	}{
+		{"issued cursor", "price", application.EncodeCursor(byPrice, key), true},
+		{"not base64", "price", "%%%", false},
+		{"not JSON", "price", base64.RawURLEncoding.EncodeToString([]byte("price")), false},
+		{"other sort", "name", application.EncodeCursor(byPrice, key), false},
+		{"missing ID", "price", application.EncodeCursor(byPrice, with(func(c *domain.ProductCursor) { c.ID = "" })), false},
+		{"price not a number", "price", application.EncodeCursor(byPrice, with(func(c *domain.ProductCursor) { c.Price = "abc" })), false},
+		{"price too precise", "price", application.EncodeCursor(byPrice, with(func(c *domain.ProductCursor) { c.Price = "1.001" })), false},
+		{"price too large", "price", application.EncodeCursor(byPrice, with(func(c *domain.ProductCursor) { c.Price = "1e12" })), false},
+		{"stock out of range", "-stock", application.EncodeCursor(byStock, with(func(c *domain.ProductCursor) { c.Stock = 1 << 40 })), false},
+		{"price unused by the sort", "-stock", application.EncodeCursor(byStock, with(func(c *domain.ProductCursor) { c.Price = "abc" })), true},
+		{"created_at out of range", "", application.EncodeCursor(domain.DefaultProductSort, with(func(c *domain.ProductCursor) {
+			c.CreatedAt = time.Date(1, 1, 1, 0, 0, 0, 0, time.FixedZone("", 3600))
+		})), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := service.GetAll(context.Background(), application.ProductListFiltersDTO{Sort: c.sort, Cursor: c.cursor, Limit: 1})
+			if c.valid {
+				if err != nil {
+					t.Fatalf("GetAll: %v", err)
+				}
+				return
+			}
+			assertAppError(t, err, 400)
+		})
+	}
+}
+
+func TestStockHistoryRejectsNegativeLimit(t *testing.T) {
+	service, repo := newService(t)
+	product := storeLegacyProduct(t, repo, "Television")
+
+	_, err := service.StockHistory(context.Background(), product.ID, application.StockHistoryFiltersDTO{Limit: -1})
+	appErr := assertAppError(t, err, 400)
+	if appErr.Details["Limit"] == nil {
+		t.Fatalf("details %v, want Limit", appErr.Details)
+	}
+}
+
+func TestStockHistoryNeedsProductRead(t *testing.T) {
+	products := memory.NewProductRepository()
+	movements := memory.NewStockMovementRepository()
+	uow := memory.NewUnitOfWork(products, movements, memory.NewStockReservationRepository(products))
+	policy := authz.NewPolicy(map[string][]string{"user": {"product:write"}})
+	service := application.NewProductService(products, movements, uow, policy)
+	product := storeLegacyProduct(t, products, "Television")
+
+	_, err := service.StockHistory(context.Background(), product.ID, application.StockHistoryFiltersDTO{})
+	assertAppError(t, err, 401)
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1", Roles: []string{"user"}})
+	_, err = service.StockHistory(ctx, product.ID, application.StockHistoryFiltersDTO{})
+	assertAppError(t, err, 403)
+}
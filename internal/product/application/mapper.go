@@ -1,20 +1,59 @@
 package application
 
 import (
+	"encoding/json"
+
 	"go-architecture/internal/product/domain"
 )
 
 func ToProductResponseDTO(product *domain.Product) ProductResponseDTO {
-	return ProductResponseDTO{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price.Value(),
-		Stock:       product.Stock,
-		Category:    product.Category,
-		Active:      product.Active,
-		CreatedAt:   product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:   product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	dto := ProductResponseDTO{
+		ID:                 product.ID,
+		Name:               product.Name,
+		Description:        product.Description,
+		Price:              product.Price.String(),
+		Currency:           product.Price.Currency().Code(),
+		Stock:              product.Stock,
+		Reserved:           product.Reserved,
+		Available:          product.Available(),
+		Category:           product.Category,
+		Active:             product.IsActive(),
+		Status:             string(product.Status),
+		AllowedTransitions: statusNames(product.AllowedTransitions()),
+		CreatedAt:          product.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:          product.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedBy:          product.CreatedBy,
+		UpdatedBy:          product.UpdatedBy,
+		Version:            product.Version,
+		DeletedBy:          product.DeletedBy,
+	}
+	if product.DeletedAt != nil {
+		dto.DeletedAt = product.DeletedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return dto
+}
+
+func statusNames(statuses []domain.ProductStatus) []string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return names
+}
+
+// ToProductPatchDTO returns the patchable view of a product. It copies the
+// fields, so it does not change with the product.
+func ToProductPatchDTO(product *domain.Product) ProductPatchDTO {
+	p := *product
+	price := json.Number(p.Price.String())
+	currency := p.Price.Currency().Code()
+	return ProductPatchDTO{
+		Name:        &p.Name,
+		Description: &p.Description,
+		Price:       &price,
+		Currency:    &currency,
+		Stock:       &p.Stock,
+		Category:    &p.Category,
 	}
 }
 
@@ -25,3 +64,53 @@ func ToProductResponseDTOList(products []*domain.Product) []ProductResponseDTO {
 	}
 	return dtos
 }
+
+// ToProductSearchResultDTO highlights the terms in the name and in a snippet
+// of the description.
+func ToProductSearchResultDTO(match *domain.ProductMatch, terms []string) ProductSearchResultDTO {
+	return ProductSearchResultDTO{
+		ProductResponseDTO: ToProductResponseDTO(match.Product),
+		Rank:               match.Rank,
+		Highlights: ProductHighlightsDTO{
+			Name:        highlight(match.Product.Name, terms, 0),
+			Description: highlight(match.Product.Description, terms, snippetLength),
+		},
+	}
+}
+
+func ToStockMovementDTO(movement *domain.StockMovement) StockMovementDTO {
+	return StockMovementDTO{
+		ID:         movement.ID,
+		ProductID:  movement.ProductID,
+		Quantity:   movement.Quantity,
+		Reason:     string(movement.Reason),
+		StockAfter: movement.StockAfter,
+		Note:       movement.Note,
+		CreatedAt:  movement.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedBy:  movement.CreatedBy,
+	}
+}
+
+func ToStockMovementDTOList(movements []*domain.StockMovement) []StockMovementDTO {
+	dtos := make([]StockMovementDTO, len(movements))
+	for i, movement := range movements {
+		dtos[i] = ToStockMovementDTO(movement)
+	}
+	return dtos
+}
+
+func ToStockReservationDTO(reservation *domain.StockReservation) StockReservationDTO {
+	dto := StockReservationDTO{
+		ID:        reservation.ID,
+		ProductID: reservation.ProductID,
+		Quantity:  reservation.Quantity,
+		Status:    string(reservation.Status),
+		ExpiresAt: reservation.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt: reservation.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedBy: reservation.CreatedBy,
+	}
+	if reservation.ResolvedAt != nil {
+		dto.ResolvedAt = reservation.ResolvedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return dto
+}
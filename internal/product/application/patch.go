@@ -0,0 +1,66 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/shared/patch"
+)
+
+// applyPatch applies document to original and decodes the result. Unknown
+// members, such as "id" or "version", cannot be patched in.
+func applyPatch(apply func(doc, patch []byte) ([]byte, error), original ProductPatchDTO, document []byte) (ProductPatchDTO, error) {
+	var patched ProductPatchDTO
+
+	raw, err := json.Marshal(original)
+	if err != nil {
+		return patched, apperrors.NewInternalError("Failed to encode product", err)
+	}
+
+	result, err := apply(raw, document)
+	if err != nil {
+		return patched, patchError(err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(result))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&patched); err != nil {
+		return patched, apperrors.NewValidationError("Patched product is invalid", map[string]interface{}{"error": err.Error()})
+	}
+
+	return patched, nil
+}
+
+// patchError maps a failure to apply a patch to a response: a malformed
+// patch is a bad request, a failed "test" a conflict with the current state,
+// and a patch naming missing locations cannot be processed.
+func patchError(err error) error {
+	details := map[string]interface{}{"error": err.Error()}
+	switch {
+	case errors.Is(err, patch.ErrMalformed):
+		return apperrors.NewValidationError("Malformed patch document", details)
+	case errors.Is(err, patch.ErrTestFailed):
+		appErr := apperrors.NewAppError(409, "Patch test operation failed", apperrors.ErrConflict)
+		appErr.Details = details
+		return appErr
+	default:
+		appErr := apperrors.NewAppError(422, "Patch cannot be applied to the product", apperrors.ErrInvalidInput)
+		appErr.Details = details
+		return appErr
+	}
+}
+
+// changedFields lists the fields, by struct field name, whose values differ.
+func changedFields(before, after ProductPatchDTO) []string {
+	var fields []string
+	b, a := reflect.ValueOf(before), reflect.ValueOf(after)
+	for i := 0; i < b.NumField(); i++ {
+		if !reflect.DeepEqual(b.Field(i).Interface(), a.Field(i).Interface()) {
+			fields = append(fields, b.Type().Field(i).Name)
+		}
+	}
+	return fields
+}
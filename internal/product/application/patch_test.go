@@ -0,0 +1,165 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go-architecture/internal/product/application"
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/product/infra/memory"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/shared/patch"
+)
+
+type allowAll struct{}
+
+func (allowAll) Authorize(ctx context.Context, action, resource string) error {
+	return nil
+}
+
+// newService returns a service over an empty in-memory catalog, along with
+// the catalog so products can be stored without validation.
+func newService(t *testing.T) (*application.ProductService, domain.ProductRepository) {
+	t.Helper()
+
+	products := memory.NewProductRepository()
+	movements := memory.NewStockMovementRepository()
+	reservations := memory.NewStockReservationRepository(products)
+	uow := memory.NewUnitOfWork(products, movements, reservations)
+	return application.NewProductService(products, movements, uow, allowAll{}), products
+}
+
+// storeLegacyProduct stores a product whose category, "tv", is shorter than
+// the API accepts today.
+func storeLegacyProduct(t *testing.T, repo domain.ProductRepository, name string) *domain.Product {
+	t.Helper()
+
+	price, err := domain.ParsePrice("199.99", "USD")
+	if err != nil {
+		t.Fatalf("ParsePrice: %v", err)
+	}
+	product, err := domain.NewProduct(name, "", price, 10, "tv", "creator")
+	if err != nil {
+		t.Fatalf("NewProduct: %v", err)
+	}
+	if err := repo.Create(context.Background(), product); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return product
+}
+
+func TestPatchValidatesChangedFieldsOnly(t *testing.T) {
+	cases := []struct {
+		name      string
+		mediaType string
+		document  string
+		// invalid is the field the patch must be rejected for; empty when
+		// the patch applies.
+		invalid   string
+		wantStock int
+	}{
+		{"merge patch leaving an invalid field alone", patch.MergePatchType, `{"stock": 5}`, "", 5},
+		{"JSON patch leaving an invalid field alone", patch.JSONPatchType, `[{"op": "replace", "path": "/stock", "value": 7}]`, "", 7},
+		{"invalid field rewritten unchanged", patch.MergePatchType, `{"category": "tv", "stock": 3}`, "", 3},
+		{"changed field still invalid", patch.JSONPatchType, `[{"op": "replace", "path": "/category", "value": "x"}]`, "Category", 0},
+		{"changed field invalid", patch.MergePatchType, `{"name": "TV"}`, "Name", 0},
+		{"required field removed", patch.MergePatchType, `{"name": null}`, "Name", 0},
+		{"negative stock", patch.JSONPatchType, `[{"op": "replace", "path": "/stock", "value": -1}]`, "Stock", 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			service, repo := newService(t)
+			product := storeLegacyProduct(t, repo, "Television")
+
+			got, err := service.Patch(context.Background(), product.ID, c.mediaType, []byte(c.document), nil)
+			if c.invalid == "" {
+				if err != nil {
+					t.Fatalf("Patch: %v", err)
+				}
+				if got.Stock != c.wantStock || got.Category != "tv" {
+					t.Fatalf("stock %d, category %q; want %d, \"tv\"", got.Stock, got.Category, c.wantStock)
+				}
+				return
+			}
+
+			appErr := assertAppError(t, err, 400)
+			if len(appErr.Details) != 1 || appErr.Details[c.invalid] == nil {
+				t.Fatalf("details %v, want only %s", appErr.Details, c.invalid)
+			}
+		})
+	}
+}
+
+func TestPatchWithoutChanges(t *testing.T) {
+	service, repo := newService(t)
+	product := storeLegacyProduct(t, repo, "Television")
+
+	for _, document := range []string{`{}`, `{"stock": 10, "category": "tv"}`} {
+		got, err := service.Patch(context.Background(), product.ID, patch.MergePatchType, []byte(document), nil)
+		if err != nil {
+			t.Fatalf("Patch(%s): %v", document, err)
+		}
+		if got.Version != product.Version {
+			t.Fatalf("Patch(%s) moved the version from %d to %d", document, product.Version, got.Version)
+		}
+	}
+}
+
+func TestPatchErrors(t *testing.T) {
+	cases := []struct {
+		name      string
+		mediaType string
+		document  string
+		wantCode  int
+	}{
+		{"unsupported media type", "application/json", `{"stock": 1}`, 415},
+		{"malformed merge patch", patch.MergePatchType, `{"stock":`, 400},
+		{"malformed JSON patch", patch.JSONPatchType, `{"op": "add"}`, 400},
+		{"read-only member", patch.MergePatchType, `{"version": 9}`, 400},
+		{"failed test", patch.JSONPatchType, `[{"op": "test", "path": "/stock", "value": 11}]`, 409},
+		{"missing location", patch.JSONPatchType, `[{"op": "remove", "path": "/color"}]`, 422},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			service, repo := newService(t)
+			product := storeLegacyProduct(t, repo, "Television")
+
+			_, err := service.Patch(context.Background(), product.ID, c.mediaType, []byte(c.document), nil)
+			assertAppError(t, err, c.wantCode)
+		})
+	}
+}
+
+func TestRenameConflict(t *testing.T) {
+	service, repo := newService(t)
+	storeLegacyProduct(t, repo, "Television")
+	radio := storeLegacyProduct(t, repo, "Radio")
+
+	_, err := service.Patch(context.Background(), radio.ID, patch.MergePatchType, []byte(`{"name": "Television"}`), nil)
+	assertAppError(t, err, 409)
+
+	_, err = service.Update(context.Background(), radio.ID, application.UpdateProductDTO{
+		Name:     "Television",
+		Price:    json.Number("199.99"),
+		Stock:    10,
+		Category: "radios",
+	}, nil)
+	assertAppError(t, err, 409)
+}
+
+func assertAppError(t *testing.T, err error, code int) *apperrors.AppError {
+	t.Helper()
+
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("got %v, want an error with status %d", err, code)
+	}
+	if appErr.Code != code {
+		t.Fatalf("got %d %q (%v), want status %d", appErr.Code, appErr.Message, appErr.Details, code)
+	}
+	return appErr
+}
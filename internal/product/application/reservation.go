@@ -0,0 +1,235 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/shared/auth"
+	"go-architecture/internal/shared/authz"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+// sweepBatchSize is how many expired reservations ReleaseExpired loads at a
+// time.
+const sweepBatchSize = 100
+
+// ReservationService holds stock for a while, e.g. during a checkout, then
+// sells it or makes it available again.
+type ReservationService struct {
+	repo       domain.StockReservationRepository
+	uow        UnitOfWork
+	authorizer Authorizer
+	validator  *Validator
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+}
+
+// NewReservationService returns a service whose reservations last defaultTTL
+// unless the client asks for another time to live, up to maxTTL.
+func NewReservationService(repo domain.StockReservationRepository, uow UnitOfWork, authorizer Authorizer, defaultTTL, maxTTL time.Duration) *ReservationService {
+	return &ReservationService{
+		repo:       repo,
+		uow:        uow,
+		authorizer: authorizer,
+		validator:  NewValidator(),
+		defaultTTL: defaultTTL,
+		maxTTL:     maxTTL,
+	}
+}
+
+// Reserve holds units of an active product until they are confirmed,
+// released or expire. Reserving more than is available is a conflict.
+func (s *ReservationService) Reserve(ctx context.Context, productID string, dto ReserveStockDTO) (*StockReservationDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionWrite, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+
+	ttl := s.defaultTTL
+	if dto.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(dto.TTL)
+		if err != nil || ttl <= 0 {
+			return nil, apperrors.NewValidationError("ttl must be a positive duration such as 15m", map[string]interface{}{"ttl": dto.TTL})
+		}
+	}
+	if ttl > s.maxTTL {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("ttl cannot be longer than %s", s.maxTTL), map[string]interface{}{"ttl": dto.TTL})
+	}
+
+	reservation, err := domain.NewStockReservation(productID, dto.Quantity, ttl, auth.ActorID(ctx))
+	if err != nil {
+		return nil, apperrors.NewValidationError(err.Error(), nil)
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		err := repos.StockReservations().Reserve(ctx, reservation)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, apperrors.ErrNotFound):
+			return apperrors.NewNotFoundError("Product not found")
+		case errors.Is(err, domain.ErrProductNotOnSale):
+			return apperrors.NewAppError(409, "Product is not on sale", apperrors.ErrConflict)
+		case errors.Is(err, domain.ErrInsufficientStock):
+			return apperrors.NewAppError(409, "Insufficient stock available", apperrors.ErrConflict)
+		default:
+			return apperrors.NewInternalError("Failed to reserve stock", err)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToStockReservationDTO(reservation)
+	return &response, nil
+}
+
+func (s *ReservationService) Get(ctx context.Context, productID, id string) (*StockReservationDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionRead, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
+	reservation, err := findReservation(ctx, s.repo, productID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToStockReservationDTO(reservation)
+	return &response, nil
+}
+
+// Confirm sells the reserved units: they leave the stock, and the sale is
+// recorded in the stock ledger.
+func (s *ReservationService) Confirm(ctx context.Context, productID, id string) (*StockReservationDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionWrite, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
+	var reservation *domain.StockReservation
+	err := s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		var err error
+		reservation, err = findReservation(ctx, repos.StockReservations(), productID, id)
+		if err != nil {
+			return err
+		}
+
+		stock, err := repos.StockReservations().Confirm(ctx, reservation, time.Now())
+		if err != nil {
+			return resolveError(err)
+		}
+
+		if err := repos.StockMovements().Create(ctx, reservation.Sale(stock, auth.ActorID(ctx))); err != nil {
+			return apperrors.NewInternalError("Failed to record stock movement", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToStockReservationDTO(reservation)
+	return &response, nil
+}
+
+// Release makes the reserved units available again.
+func (s *ReservationService) Release(ctx context.Context, productID, id string) (*StockReservationDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionWrite, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
+	var reservation *domain.StockReservation
+	err := s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		var err error
+		reservation, err = findReservation(ctx, repos.StockReservations(), productID, id)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.StockReservations().Release(ctx, reservation, domain.ReservationReleased, time.Now()); err != nil {
+			return resolveError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToStockReservationDTO(reservation)
+	return &response, nil
+}
+
+// ReleaseExpired makes the stock of expired reservations available again and
+// returns how many it released. It runs on behalf of the server rather than
+// a client, so it needs no permission.
+func (s *ReservationService) ReleaseExpired(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	released := 0
+	for {
+		expired, err := s.repo.FindExpired(ctx, now, sweepBatchSize)
+		if err != nil {
+			return released, apperrors.NewInternalError("Failed to find expired reservations", err)
+		}
+
+		for _, reservation := range expired {
+			err := s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+				return repos.StockReservations().Release(ctx, reservation, domain.ReservationExpired, now)
+			})
+			// Skip reservations resolved since they were listed
+			if errors.Is(err, domain.ErrReservationNotPending) || errors.Is(err, apperrors.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return released, apperrors.NewInternalError("Failed to release expired reservation", err)
+			}
+			released++
+		}
+
+		if len(expired) < sweepBatchSize {
+			return released, nil
+		}
+	}
+}
+
+// findReservation loads a reservation of the product; a reservation of
+// another product is not found.
+func findReservation(ctx context.Context, repo domain.StockReservationRepository, productID, id string) (*domain.StockReservation, error) {
+	reservation, err := repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.NewNotFoundError("Reservation not found")
+		}
+		return nil, apperrors.NewInternalError("Failed to get reservation", err)
+	}
+
+	if reservation.ProductID != productID {
+		return nil, apperrors.NewNotFoundError("Reservation not found")
+	}
+
+	return reservation, nil
+}
+
+// resolveError maps a confirmation or release the repository refused.
+func resolveError(err error) error {
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return apperrors.NewNotFoundError("Reservation not found")
+	case errors.Is(err, domain.ErrReservationExpired):
+		return apperrors.NewAppError(409, "Reservation has expired", apperrors.ErrConflict)
+	case errors.Is(err, domain.ErrReservationNotPending):
+		return apperrors.NewAppError(409, "Reservation is no longer pending", apperrors.ErrConflict)
+	case errors.Is(err, domain.ErrProductNotOnSale):
+		return apperrors.NewAppError(409, "Product is not on sale", apperrors.ErrConflict)
+	default:
+		return apperrors.NewInternalError("Failed to resolve reservation", err)
+	}
+}
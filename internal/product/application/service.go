@@ -3,47 +3,86 @@ package application
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"go-architecture/internal/product/domain"
+	"go-architecture/internal/shared/auth"
+	"go-architecture/internal/shared/authz"
 	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/shared/patch"
 )
 
+// Authorizer checks that the principal in ctx may perform action on
+// resource. It is satisfied by *authz.Policy.
+type Authorizer interface {
+	Authorize(ctx context.Context, action, resource string) error
+}
+
 type ProductService struct {
-	repo      domain.ProductRepository
-	validator *Validator
+	repo       domain.ProductRepository
+	movements  domain.StockMovementRepository
+	uow        UnitOfWork
+	authorizer Authorizer
+	validator  *Validator
 }
 
-func NewProductService(repo domain.ProductRepository) *ProductService {
+func NewProductService(repo domain.ProductRepository, movements domain.StockMovementRepository, uow UnitOfWork, authorizer Authorizer) *ProductService {
 	return &ProductService{
-		repo:      repo,
-		validator: NewValidator(),
+		repo:       repo,
+		movements:  movements,
+		uow:        uow,
+		authorizer: authorizer,
+		validator:  NewValidator(),
 	}
 }
 
 func (s *ProductService) Create(ctx context.Context, dto CreateProductDTO) (*ProductResponseDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionWrite, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
 	// Validate DTO
 	if err := s.validator.Validate(dto); err != nil {
 		return nil, err
 	}
 
-	// Check if product with same name exists
-	exists, err := s.repo.ExistsByName(ctx, dto.Name)
+	price, err := domain.ParsePrice(dto.Price.String(), dto.Currency)
 	if err != nil {
-		return nil, apperrors.NewInternalError("Failed to check product existence", err)
-	}
-	if exists {
-		return nil, apperrors.NewAppError(409, "Product with this name already exists", apperrors.ErrConflict)
+		return nil, apperrors.NewValidationError(err.Error(), nil)
 	}
 
 	// Create domain entity
-	product, err := domain.NewProduct(dto.Name, dto.Description, dto.Price, dto.Stock, dto.Category)
+	product, err := domain.NewProduct(dto.Name, dto.Description, price, dto.Stock, dto.Category, auth.ActorID(ctx))
 	if err != nil {
 		return nil, apperrors.NewValidationError(err.Error(), nil)
 	}
+	if dto.Status == string(domain.StatusActive) {
+		if err := product.Activate(auth.ActorID(ctx)); err != nil {
+			return nil, apperrors.NewValidationError(err.Error(), nil)
+		}
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		// Check if product with same name exists
+		exists, err := repos.Products().ExistsByName(ctx, dto.Name)
+		if err != nil {
+			return apperrors.NewInternalError("Failed to check product existence", err)
+		}
+		if exists {
+			return apperrors.NewAppError(409, "Product with this name already exists", apperrors.ErrConflict)
+		}
+
+		// Save to repository
+		if err := repos.Products().Create(ctx, product); err != nil {
+			return apperrors.NewInternalError("Failed to create product", err)
+		}
 
-	// Save to repository
-	if err := s.repo.Create(ctx, product); err != nil {
-		return nil, apperrors.NewInternalError("Failed to create product", err)
+		// Open the stock ledger
+		return recordStockChange(ctx, repos, product, product.Stock, domain.ReasonInitial)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	response := ToProductResponseDTO(product)
@@ -63,22 +102,43 @@ func (s *ProductService) GetByID(ctx context.Context, id string) (*ProductRespon
 	return &response, nil
 }
 
-func (s *ProductService) GetAll(ctx context.Context, filtersDTO ProductListFiltersDTO) ([]ProductResponseDTO, error) {
+// GetAll returns a page of products. Pages are addressed either by offset
+// or, to stay stable while products are added, by the cursor of the previous
+// page.
+func (s *ProductService) GetAll(ctx context.Context, filtersDTO ProductListFiltersDTO) (*ProductPageDTO, error) {
 	// Validate filters
 	if err := s.validator.Validate(filtersDTO); err != nil {
 		return nil, err
 	}
 
+	// Only those who may delete products may see the deleted ones
+	if filtersDTO.IncludeDeleted {
+		if err := s.authorizer.Authorize(ctx, authz.ActionDelete, authz.ResourceProduct); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set default pagination
 	if filtersDTO.Limit == 0 {
 		filtersDTO.Limit = 20
 	}
 
-	filters := domain.ProductFilters{
-		Category: filtersDTO.Category,
-		Active:   filtersDTO.Active,
-		Limit:    filtersDTO.Limit,
-		Offset:   filtersDTO.Offset,
+	filters, err := toFilters(filtersDTO)
+	if err != nil {
+		return nil, err
+	}
+	// One extra row tells whether there is a next page
+	filters.Limit++
+
+	if filtersDTO.Cursor != "" {
+		if filtersDTO.Offset != 0 {
+			return nil, apperrors.NewValidationError("cursor and offset cannot be combined", nil)
+		}
+		after, err := DecodeCursor(filters.Sort, filtersDTO.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filters.After = after
 	}
 
 	products, err := s.repo.FindAll(ctx, filters)
@@ -86,16 +146,335 @@ func (s *ProductService) GetAll(ctx context.Context, filtersDTO ProductListFilte
 		return nil, apperrors.NewInternalError("Failed to get products", err)
 	}
 
-	return ToProductResponseDTOList(products), nil
+	total, err := s.repo.Count(ctx, filters)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to count products", err)
+	}
+
+	page := &ProductPageDTO{
+		Total:  total,
+		Limit:  filtersDTO.Limit,
+		Offset: filtersDTO.Offset,
+	}
+	if len(products) > filtersDTO.Limit {
+		products = products[:filtersDTO.Limit]
+		page.HasMore = true
+		page.NextCursor = EncodeCursor(filters.Sort, domain.CursorOf(products[len(products)-1]))
+	}
+	page.Products = ToProductResponseDTOList(products)
+
+	return page, nil
 }
 
-func (s *ProductService) Update(ctx context.Context, id string, dto UpdateProductDTO) (*ProductResponseDTO, error) {
+// Search finds products whose name or description contains every word of
+// the query, best match first.
+func (s *ProductService) Search(ctx context.Context, dto ProductSearchDTO) (*ProductSearchPageDTO, error) {
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+
+	if dto.Limit == 0 {
+		dto.Limit = 20
+	}
+
+	terms := domain.SearchTerms(dto.Q)
+	if len(terms) == 0 {
+		return nil, apperrors.NewValidationError("Search query must contain a word", map[string]interface{}{"q": dto.Q})
+	}
+	if len(terms) > domain.MaxSearchTerms {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Search query cannot have more than %d words", domain.MaxSearchTerms), map[string]interface{}{"q": dto.Q})
+	}
+
+	// One extra row tells whether there is a next page
+	matches, total, err := s.repo.Search(ctx, domain.ProductSearch{Terms: terms, Limit: dto.Limit + 1, Offset: dto.Offset})
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to search products", err)
+	}
+
+	page := &ProductSearchPageDTO{
+		Results: make([]ProductSearchResultDTO, 0, len(matches)),
+		Total:   total,
+		Limit:   dto.Limit,
+		Offset:  dto.Offset,
+	}
+	if len(matches) > dto.Limit {
+		matches = matches[:dto.Limit]
+		page.HasMore = true
+	}
+	for _, match := range matches {
+		page.Results = append(page.Results, ToProductSearchResultDTO(match, terms))
+	}
+
+	return page, nil
+}
+
+// Update replaces a product. When expectedVersion is set the update only
+// succeeds if the stored product is still at that version (If-Match).
+func (s *ProductService) Update(ctx context.Context, id string, dto UpdateProductDTO, expectedVersion *int64) (*ProductResponseDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionWrite, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
 	// Validate DTO
 	if err := s.validator.Validate(dto); err != nil {
 		return nil, err
 	}
 
-	// Get existing product
+	var product *domain.Product
+	err := s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		// Get existing product
+		var err error
+		product, err = repos.Products().FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return apperrors.NewNotFoundError("Product not found")
+			}
+			return apperrors.NewInternalError("Failed to get product", err)
+		}
+
+		if expectedVersion != nil && *expectedVersion != product.Version {
+			return apperrors.NewPreconditionFailedError("Product has been modified since it was retrieved")
+		}
+
+		if err := checkRename(ctx, repos, product, dto.Name); err != nil {
+			return err
+		}
+
+		currency := dto.Currency
+		if currency == "" {
+			currency = product.Price.Currency().Code()
+		}
+
+		price, err := domain.ParsePrice(dto.Price.String(), currency)
+		if err != nil {
+			return apperrors.NewValidationError(err.Error(), nil)
+		}
+
+		// Update domain entity
+		previousStock := product.Stock
+		if err := product.Update(dto.Name, dto.Description, price, dto.Stock, dto.Category, auth.ActorID(ctx)); err != nil {
+			return productUpdateError(err)
+		}
+
+		// Save changes
+		if err := repos.Products().Update(ctx, product); err != nil {
+			return updateError(err, expectedVersion != nil)
+		}
+
+		return recordStockChange(ctx, repos, product, product.Stock-previousStock, domain.ReasonCorrection)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToProductResponseDTO(product)
+	return &response, nil
+}
+
+// Patch applies a JSON Merge Patch or JSON Patch document, given its media
+// type, to the writable fields of a product. Only the fields the patch
+// changes are validated; the result must still satisfy the product's
+// invariants. A patch that changes nothing does not touch the product.
+func (s *ProductService) Patch(ctx context.Context, id, mediaType string, document []byte, expectedVersion *int64) (*ProductResponseDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionWrite, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
+	var apply func(doc, patch []byte) ([]byte, error)
+	switch mediaType {
+	case patch.MergePatchType:
+		apply = patch.MergePatch
+	case patch.JSONPatchType:
+		apply = patch.JSONPatch
+	default:
+		return nil, apperrors.NewAppError(415, "Unsupported patch media type", apperrors.ErrBadRequest)
+	}
+
+	var product *domain.Product
+	err := s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		var err error
+		product, err = repos.Products().FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return apperrors.NewNotFoundError("Product not found")
+			}
+			return apperrors.NewInternalError("Failed to get product", err)
+		}
+
+		if expectedVersion != nil && *expectedVersion != product.Version {
+			return apperrors.NewPreconditionFailedError("Product has been modified since it was retrieved")
+		}
+
+		original := ToProductPatchDTO(product)
+		patched, err := applyPatch(apply, original, document)
+		if err != nil {
+			return err
+		}
+
+		changed := changedFields(original, patched)
+		if len(changed) == 0 {
+			return nil
+		}
+		if err := s.validator.ValidateFields(patched, changed...); err != nil {
+			return err
+		}
+
+		if err := checkRename(ctx, repos, product, *patched.Name); err != nil {
+			return err
+		}
+
+		price, err := domain.ParsePrice(patched.Price.String(), *patched.Currency)
+		if err != nil {
+			return apperrors.NewValidationError(err.Error(), nil)
+		}
+
+		description := ""
+		if patched.Description != nil {
+			description = *patched.Description
+		}
+
+		previousStock := product.Stock
+		if err := product.Update(*patched.Name, description, price, *patched.Stock, *patched.Category, auth.ActorID(ctx)); err != nil {
+			return productUpdateError(err)
+		}
+
+		if err := repos.Products().Update(ctx, product); err != nil {
+			return updateError(err, expectedVersion != nil)
+		}
+
+		return recordStockChange(ctx, repos, product, product.Stock-previousStock, domain.ReasonCorrection)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToProductResponseDTO(product)
+	return &response, nil
+}
+
+// Transition moves a product to another lifecycle status. A move the
+// lifecycle does not allow is a conflict whose details list the allowed
+// statuses.
+func (s *ProductService) Transition(ctx context.Context, id string, dto TransitionProductDTO, expectedVersion *int64) (*ProductResponseDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionWrite, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+	status, err := domain.ParseProductStatus(dto.Status)
+	if err != nil {
+		return nil, apperrors.NewValidationError(err.Error(), map[string]interface{}{"status": dto.Status})
+	}
+
+	var product *domain.Product
+	err = s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		var err error
+		product, err = repos.Products().FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return apperrors.NewNotFoundError("Product not found")
+			}
+			return apperrors.NewInternalError("Failed to get product", err)
+		}
+
+		if expectedVersion != nil && *expectedVersion != product.Version {
+			return apperrors.NewPreconditionFailedError("Product has been modified since it was retrieved")
+		}
+
+		if err := product.TransitionTo(status, auth.ActorID(ctx)); err != nil {
+			appErr := apperrors.NewAppError(409, fmt.Sprintf("Product cannot move from %s to %s", product.Status, status), apperrors.ErrConflict)
+			appErr.Details["status"] = string(product.Status)
+			appErr.Details["allowed_transitions"] = statusNames(product.AllowedTransitions())
+			return appErr
+		}
+
+		if err := repos.Products().Update(ctx, product); err != nil {
+			return updateError(err, expectedVersion != nil)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToProductResponseDTO(product)
+	return &response, nil
+}
+
+// AdjustStock moves stock in or out of a product for a reason and records
+// the movement in the ledger. Taking out more than is in stock is a conflict.
+func (s *ProductService) AdjustStock(ctx context.Context, id string, dto StockAdjustmentDTO, expectedVersion *int64) (*StockAdjustmentResultDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionWrite, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+	reason, err := domain.ParseMovementReason(dto.Reason)
+	if err != nil {
+		return nil, apperrors.NewValidationError(err.Error(), map[string]interface{}{"reason": dto.Reason})
+	}
+
+	var product *domain.Product
+	var movement *domain.StockMovement
+	err = s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		var err error
+		product, err = repos.Products().FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return apperrors.NewNotFoundError("Product not found")
+			}
+			return apperrors.NewInternalError("Failed to get product", err)
+		}
+
+		if expectedVersion != nil && *expectedVersion != product.Version {
+			return apperrors.NewPreconditionFailedError("Product has been modified since it was retrieved")
+		}
+
+		movement, err = product.AdjustStock(dto.Quantity, reason, dto.Note, auth.ActorID(ctx))
+		if err != nil {
+			return stockAdjustmentError(err, product)
+		}
+
+		if err := repos.Products().Update(ctx, product); err != nil {
+			return updateError(err, expectedVersion != nil)
+		}
+
+		if err := repos.StockMovements().Create(ctx, movement); err != nil {
+			return apperrors.NewInternalError("Failed to record stock movement", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &StockAdjustmentResultDTO{
+		Movement: ToStockMovementDTO(movement),
+		Product:  ToProductResponseDTO(product),
+	}, nil
+}
+
+// StockHistory returns a page of a product's stock movements, newest first,
+// with the stock the ledger adds up to next to the product's stock.
+func (s *ProductService) StockHistory(ctx context.Context, id string, filtersDTO StockHistoryFiltersDTO) (*StockHistoryPageDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionRead, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(filtersDTO); err != nil {
+		return nil, err
+	}
+
+	if filtersDTO.Limit == 0 {
+		filtersDTO.Limit = 20
+	}
+
 	product, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrNotFound) {
@@ -104,34 +483,202 @@ func (s *ProductService) Update(ctx context.Context, id string, dto UpdateProduc
 		return nil, apperrors.NewInternalError("Failed to get product", err)
 	}
 
-	// Update domain entity
-	if err := product.Update(dto.Name, dto.Description, dto.Price, dto.Stock, dto.Category); err != nil {
-		return nil, apperrors.NewValidationError(err.Error(), nil)
+	// One extra row tells whether there is a next page
+	movements, err := s.movements.FindAll(ctx, domain.StockMovementFilters{
+		ProductID: product.ID,
+		Limit:     filtersDTO.Limit + 1,
+		Offset:    filtersDTO.Offset,
+	})
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to get stock movements", err)
 	}
 
-	// Save changes
-	if err := s.repo.Update(ctx, product); err != nil {
-		return nil, apperrors.NewInternalError("Failed to update product", err)
+	total, err := s.movements.Count(ctx, product.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to count stock movements", err)
 	}
 
-	response := ToProductResponseDTO(product)
-	return &response, nil
+	balance, err := s.movements.Balance(ctx, product.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to get stock balance", err)
+	}
+
+	page := &StockHistoryPageDTO{
+		Total:         total,
+		Limit:         filtersDTO.Limit,
+		Offset:        filtersDTO.Offset,
+		Stock:         product.Stock,
+		LedgerBalance: balance,
+	}
+	if len(movements) > filtersDTO.Limit {
+		movements = movements[:filtersDTO.Limit]
+		page.HasMore = true
+	}
+	page.Movements = ToStockMovementDTOList(movements)
+
+	return page, nil
 }
 
+// Delete soft-deletes a product. It disappears from reads but keeps its
+// data and name until it is restored or purged.
 func (s *ProductService) Delete(ctx context.Context, id string) error {
-	// Check if product exists
-	_, err := s.repo.FindByID(ctx, id)
-	if err != nil {
-		if errors.Is(err, apperrors.ErrNotFound) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionDelete, authz.ResourceProduct); err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		// Check if product exists
+		product, err := repos.Products().FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return apperrors.NewNotFoundError("Product not found")
+			}
+			return apperrors.NewInternalError("Failed to get product", err)
+		}
+
+		if err := product.Delete(auth.ActorID(ctx)); err != nil {
 			return apperrors.NewNotFoundError("Product not found")
 		}
-		return apperrors.NewInternalError("Failed to get product", err)
+
+		if err := repos.Products().Update(ctx, product); err != nil {
+			return updateError(err, false)
+		}
+
+		return nil
+	})
+}
+
+// Restore brings back a soft-deleted product. It needs the same permission
+// as Delete.
+func (s *ProductService) Restore(ctx context.Context, id string) (*ProductResponseDTO, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionDelete, authz.ResourceProduct); err != nil {
+		return nil, err
+	}
+
+	var product *domain.Product
+	err := s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		var err error
+		product, err = repos.Products().FindByIDIncludingDeleted(ctx, id)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return apperrors.NewNotFoundError("Product not found")
+			}
+			return apperrors.NewInternalError("Failed to get product", err)
+		}
+
+		if err := product.Restore(auth.ActorID(ctx)); err != nil {
+			return apperrors.NewAppError(409, "Product is not deleted", apperrors.ErrConflict)
+		}
+
+		if err := repos.Products().Update(ctx, product); err != nil {
+			return updateError(err, false)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToProductResponseDTO(product)
+	return &response, nil
+}
+
+// Purge permanently removes the products that were soft-deleted more than
+// retention ago and returns how many there were.
+func (s *ProductService) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	if err := s.authorizer.Authorize(ctx, authz.ActionDelete, authz.ResourceProduct); err != nil {
+		return 0, err
+	}
+	if retention < 0 {
+		return 0, apperrors.NewValidationError("Retention cannot be negative", nil)
+	}
+
+	purged, err := s.repo.Purge(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, apperrors.NewInternalError("Failed to purge products", err)
 	}
+	return purged, nil
+}
 
-	// Delete product
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return apperrors.NewInternalError("Failed to delete product", err)
+// checkRename fails with a conflict when renaming product to name would
+// clash with another product's name.
+func checkRename(ctx context.Context, repos Repositories, product *domain.Product, name string) error {
+	if name == product.Name {
+		return nil
 	}
 
+	exists, err := repos.Products().ExistsByName(ctx, name)
+	if err != nil {
+		return apperrors.NewInternalError("Failed to check product existence", err)
+	}
+	if exists {
+		return apperrors.NewAppError(409, "Product with this name already exists", apperrors.ErrConflict)
+	}
 	return nil
 }
+
+// recordStockChange adds the movement that brought the product to its current
+// stock to the ledger. A zero change is not recorded, except to open the
+// ledger of a new product.
+func recordStockChange(ctx context.Context, repos Repositories, product *domain.Product, quantity int, reason domain.MovementReason) error {
+	if quantity == 0 && reason != domain.ReasonInitial {
+		return nil
+	}
+
+	movement, err := domain.NewStockMovement(product, quantity, reason, "", product.UpdatedBy)
+	if err != nil {
+		return apperrors.NewInternalError("Failed to record stock movement", err)
+	}
+	if err := repos.StockMovements().Create(ctx, movement); err != nil {
+		return apperrors.NewInternalError("Failed to record stock movement", err)
+	}
+	return nil
+}
+
+// stockAdjustmentError maps an adjustment the product rejected. Running out
+// of stock is a conflict whose details give the stock left and how much of
+// it is available.
+func stockAdjustmentError(err error, product *domain.Product) error {
+	switch {
+	case errors.Is(err, domain.ErrInsufficientStock):
+		appErr := apperrors.NewAppError(409, "Insufficient stock", apperrors.ErrConflict)
+		appErr.Details["stock"] = product.Stock
+		appErr.Details["available"] = product.Available()
+		return appErr
+	case errors.Is(err, domain.ErrProductArchived):
+		return apperrors.NewAppError(409, "Archived products cannot be changed", apperrors.ErrConflict)
+	default:
+		return apperrors.NewValidationError(err.Error(), nil)
+	}
+}
+
+// productUpdateError maps a change the product rejected: archived products
+// and stock below the reserved quantity are conflicts, anything else invalid
+// input.
+func productUpdateError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrProductArchived):
+		return apperrors.NewAppError(409, "Archived products cannot be changed", apperrors.ErrConflict)
+	case errors.Is(err, domain.ErrStockReserved):
+		return apperrors.NewAppError(409, "Stock cannot be less than the reserved quantity", apperrors.ErrConflict)
+	default:
+		return apperrors.NewValidationError(err.Error(), nil)
+	}
+}
+
+// updateError maps repository errors from a version-checked save. A lost race
+// is reported as 412 when the client sent If-Match and as 409 otherwise.
+func updateError(err error, conditional bool) error {
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return apperrors.NewNotFoundError("Product not found")
+	case errors.Is(err, apperrors.ErrConcurrencyConflict):
+		if conditional {
+			return apperrors.NewPreconditionFailedError("Product has been modified since it was retrieved")
+		}
+		return apperrors.NewConflictError("Product was modified concurrently, retry with the latest version")
+	default:
+		return apperrors.NewInternalError("Failed to update product", err)
+	}
+}
@@ -0,0 +1,21 @@
+package application
+
+import (
+	"context"
+
+	"go-architecture/internal/product/domain"
+)
+
+// Repositories gives access to repositories bound to the same transaction.
+type Repositories interface {
+	Products() domain.ProductRepository
+	StockMovements() domain.StockMovementRepository
+	StockReservations() domain.StockReservationRepository
+}
+
+// UnitOfWork runs a function inside a single database transaction. The
+// transaction is committed if fn returns nil and rolled back otherwise; the
+// error returned by fn is passed through unchanged.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
@@ -18,7 +18,20 @@ func NewValidator() *Validator {
 }
 
 func (v *Validator) Validate(data interface{}) error {
-	if err := v.validate.Struct(data); err != nil {
+	return validationError(v.validate.Struct(data))
+}
+
+// ValidateFields checks only the named fields of a struct, such as the fields
+// a patch changed.
+func (v *Validator) ValidateFields(data interface{}, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return validationError(v.validate.StructPartial(data, fields...))
+}
+
+func validationError(err error) error {
+	if err != nil {
 		validationErrors := err.(validator.ValidationErrors)
 		details := make(map[string]interface{})
 		
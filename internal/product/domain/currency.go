@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// DefaultCurrency is used when a price is given without a currency code.
+const DefaultCurrency = "USD"
+
+// Currency is an ISO-4217 currency with the number of minor units (decimal
+// places) it is quoted in.
+type Currency struct {
+	code       string
+	minorUnits int
+}
+
+// currencies lists the supported ISO-4217 codes. Storage columns are
+// DECIMAL(10,2), so only currencies with at most two minor units are accepted.
+var currencies = map[string]int{
+	"AUD": 2,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CLP": 0,
+	"CNY": 2,
+	"COP": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"MXN": 2,
+	"PEN": 2,
+	"USD": 2,
+}
+
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		code = DefaultCurrency
+	}
+
+	minorUnits, ok := currencies[code]
+	if !ok {
+		return Currency{}, ErrUnsupportedCurrency
+	}
+
+	return Currency{code: code, minorUnits: minorUnits}, nil
+}
+
+func (c Currency) Code() string {
+	return c.code
+}
+
+func (c Currency) MinorUnits() int {
+	return c.minorUnits
+}
+
+// maxAmount is the largest catalog price storage can hold in the currency, in
+// minor units: 9999999999 for USD (99999999.99), 99999999 for JPY.
+func (c Currency) maxAmount() int64 {
+	limit := int64(1)
+	for i := 0; i < maxWholeDigits+c.minorUnits; i++ {
+		limit *= 10
+	}
+	return limit - 1
+}
+
+func (c Currency) String() string {
+	return c.code
+}
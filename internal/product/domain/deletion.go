@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrProductDeleted    = errors.New("product is already deleted")
+	ErrProductNotDeleted = errors.New("product is not deleted")
+)
+
+// IsDeleted reports whether the product is soft-deleted.
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
+// Delete soft-deletes the product. It keeps its data, and its name, until it
+// is restored or purged. Like any other change, it is recorded in UpdatedAt
+// and UpdatedBy.
+func (p *Product) Delete(deletedBy string) error {
+	if p.IsDeleted() {
+		return ErrProductDeleted
+	}
+
+	now := time.Now()
+	p.DeletedAt = &now
+	p.DeletedBy = deletedBy
+	p.UpdatedAt = now
+	p.UpdatedBy = deletedBy
+	return nil
+}
+
+// Restore undoes Delete.
+func (p *Product) Restore(restoredBy string) error {
+	if !p.IsDeleted() {
+		return ErrProductNotDeleted
+	}
+
+	p.DeletedAt = nil
+	p.DeletedBy = ""
+	p.UpdatedAt = time.Now()
+	p.UpdatedBy = restoredBy
+	return nil
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeleteAndRestoreRecordTheChange(t *testing.T) {
+	price, err := ParsePrice("10.00", "USD")
+	if err != nil {
+		t.Fatalf("ParsePrice: %v", err)
+	}
+	product, err := NewProduct("Desk Lamp", "", price, 5, "lighting", "creator")
+	if err != nil {
+		t.Fatalf("NewProduct: %v", err)
+	}
+	created := product.UpdatedAt
+
+	time.Sleep(time.Millisecond)
+	if err := product.Delete("remover"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if !product.IsDeleted() || product.DeletedBy != "remover" {
+		t.Fatalf("deleted at %v by %q, want a deletion by remover", product.DeletedAt, product.DeletedBy)
+	}
+	if product.UpdatedBy != "remover" || !product.UpdatedAt.Equal(*product.DeletedAt) || !product.UpdatedAt.After(created) {
+		t.Fatalf("updated at %v by %q, want the deletion at %v by remover", product.UpdatedAt, product.UpdatedBy, *product.DeletedAt)
+	}
+	if err := product.Delete("remover"); !errors.Is(err, ErrProductDeleted) {
+		t.Fatalf("second Delete: got %v, want ErrProductDeleted", err)
+	}
+
+	deleted := product.UpdatedAt
+	time.Sleep(time.Millisecond)
+	if err := product.Restore("restorer"); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if product.IsDeleted() || product.DeletedBy != "" {
+		t.Fatalf("still deleted at %v by %q after Restore", product.DeletedAt, product.DeletedBy)
+	}
+	if product.UpdatedBy != "restorer" || !product.UpdatedAt.After(deleted) {
+		t.Fatalf("updated at %v by %q, want a later update by restorer", product.UpdatedAt, product.UpdatedBy)
+	}
+	if err := product.Restore("restorer"); !errors.Is(err, ErrProductNotDeleted) {
+		t.Fatalf("second Restore: got %v, want ErrProductNotDeleted", err)
+	}
+}
@@ -1,29 +1,324 @@
 package domain
 
 import (
-	"fmt"
+	"errors"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
+var (
+	ErrInvalidAmount    = errors.New("amount must be a plain decimal number")
+	ErrPricePrecision   = errors.New("amount has more decimal places than the currency allows")
+	ErrPriceOverflow    = errors.New("amount is out of range")
+	ErrPriceTooLarge    = errors.New("price cannot be more than 99999999.99")
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+)
+
+// RoundingMode controls how amounts with more decimal places than the
+// currency allows are brought to the currency's minor unit.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds ties away from zero (1.005 -> 1.01).
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfDown rounds ties towards zero (1.005 -> 1.00).
+	RoundHalfDown
+	// RoundHalfEven rounds ties to the even neighbour (banker's rounding).
+	RoundHalfEven
+	// RoundDown truncates towards zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling
+)
+
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+// maxWholeDigits is how many digits a catalog price may have before the
+// decimal point: storage columns are DECIMAL(10,2).
+const maxWholeDigits = 8
+
+// Price is an exact monetary amount stored as an integer number of minor
+// units (e.g. cents) together with its ISO-4217 currency.
 type Price struct {
-	value float64
+	amount   int64
+	currency Currency
 }
 
-func NewPrice(value float64) (Price, error) {
-	if value <= 0 {
+// NewPrice builds a catalog price from an amount in minor units. Catalog
+// prices must be greater than zero.
+func NewPrice(minorAmount int64, currencyCode string) (Price, error) {
+	currency, err := ParseCurrency(currencyCode)
+	if err != nil {
+		return Price{}, err
+	}
+
+	if minorAmount <= 0 {
+		return Price{}, ErrInvalidPrice
+	}
+	if minorAmount > currency.maxAmount() {
+		return Price{}, ErrPriceTooLarge
+	}
+
+	return Price{amount: minorAmount, currency: currency}, nil
+}
+
+// ParsePrice parses a decimal string such as "1299.99" into a catalog price.
+// Amounts that cannot be represented exactly in the currency are rejected.
+func ParsePrice(amount, currencyCode string) (Price, error) {
+	return parsePrice(amount, currencyCode, nil)
+}
+
+// ParsePriceRounded parses a decimal string and rounds it to the currency's
+// minor unit using the given mode.
+func ParsePriceRounded(amount, currencyCode string, mode RoundingMode) (Price, error) {
+	return parsePrice(amount, currencyCode, &mode)
+}
+
+// ZeroPrice returns a zero amount in the given currency, useful as the
+// starting point of a sum.
+func ZeroPrice(currencyCode string) (Price, error) {
+	currency, err := ParseCurrency(currencyCode)
+	if err != nil {
+		return Price{}, err
+	}
+	return Price{currency: currency}, nil
+}
+
+func parsePrice(amount, currencyCode string, mode *RoundingMode) (Price, error) {
+	currency, err := ParseCurrency(currencyCode)
+	if err != nil {
+		return Price{}, err
+	}
+
+	value, err := parseDecimal(amount)
+	if err != nil {
+		return Price{}, err
+	}
+
+	minor, err := toMinorUnits(value, currency.minorUnits, mode)
+	if err != nil {
+		return Price{}, err
+	}
+
+	if minor <= 0 {
 		return Price{}, ErrInvalidPrice
 	}
+	if minor > currency.maxAmount() {
+		return Price{}, ErrPriceTooLarge
+	}
 
-	return Price{value: value}, nil
+	return Price{amount: minor, currency: currency}, nil
 }
 
-func (p Price) Value() float64 {
-	return p.value
+// MinorAmount returns the amount in minor units (e.g. cents for USD).
+func (p Price) MinorAmount() int64 {
+	return p.amount
 }
 
+func (p Price) Currency() Currency {
+	return p.currency
+}
+
+// String formats the amount as a plain decimal using the currency's minor
+// units, e.g. "1299.99" or "1500" for JPY.
 func (p Price) String() string {
-	return fmt.Sprintf("%.2f", p.value)
+	scale := p.currency.minorUnits
+	negative := p.amount < 0
+
+	abs := uint64(p.amount)
+	if negative {
+		abs = -abs
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+
+	if negative {
+		return "-" + digits
+	}
+	return digits
+}
+
+func (p Price) IsZero() bool {
+	return p.amount == 0
+}
+
+func (p Price) IsPositive() bool {
+	return p.amount > 0
+}
+
+func (p Price) IsNegative() bool {
+	return p.amount < 0
+}
+
+func (p Price) Add(other Price) (Price, error) {
+	if err := p.sameCurrency(other); err != nil {
+		return Price{}, err
+	}
+
+	if (other.amount > 0 && p.amount > math.MaxInt64-other.amount) ||
+		(other.amount < 0 && p.amount < math.MinInt64-other.amount) {
+		return Price{}, ErrPriceOverflow
+	}
+
+	return Price{amount: p.amount + other.amount, currency: p.currency}, nil
+}
+
+func (p Price) Sub(other Price) (Price, error) {
+	if err := p.sameCurrency(other); err != nil {
+		return Price{}, err
+	}
+
+	if (other.amount < 0 && p.amount > math.MaxInt64+other.amount) ||
+		(other.amount > 0 && p.amount < math.MinInt64+other.amount) {
+		return Price{}, ErrPriceOverflow
+	}
+
+	return Price{amount: p.amount - other.amount, currency: p.currency}, nil
+}
+
+// Mul multiplies the price by an integer quantity, e.g. for an order line.
+func (p Price) Mul(quantity int64) (Price, error) {
+	if p.amount == 0 || quantity == 0 {
+		return Price{currency: p.currency}, nil
+	}
+
+	result := p.amount * quantity
+	if result/quantity != p.amount || (quantity == -1 && p.amount == math.MinInt64) {
+		return Price{}, ErrPriceOverflow
+	}
+
+	return Price{amount: result, currency: p.currency}, nil
+}
+
+// MulDecimal multiplies the price by a decimal factor such as "0.15" for a
+// tax rate and rounds the result to the currency's minor unit.
+func (p Price) MulDecimal(factor string, mode RoundingMode) (Price, error) {
+	f, err := parseDecimal(factor)
+	if err != nil {
+		return Price{}, err
+	}
+
+	value := new(big.Rat).Mul(big.NewRat(p.amount, 1), f)
+
+	amount, err := roundRat(value, mode)
+	if err != nil {
+		return Price{}, err
+	}
+
+	return Price{amount: amount, currency: p.currency}, nil
+}
+
+// Cmp compares two prices of the same currency and returns -1, 0 or +1.
+func (p Price) Cmp(other Price) (int, error) {
+	if err := p.sameCurrency(other); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case p.amount < other.amount:
+		return -1, nil
+	case p.amount > other.amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
 }
 
 func (p Price) Equals(other Price) bool {
-	return p.value == other.value
+	return p.amount == other.amount && p.currency == other.currency
+}
+
+func (p Price) sameCurrency(other Price) error {
+	if p.currency != other.currency {
+		return ErrCurrencyMismatch
+	}
+	return nil
+}
+
+func parseDecimal(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 40 || !decimalPattern.MatchString(s) {
+		return nil, ErrInvalidAmount
+	}
+
+	value, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, ErrInvalidAmount
+	}
+	return value, nil
+}
+
+// toMinorUnits scales a decimal value to minor units. When mode is nil the
+// conversion must be exact.
+func toMinorUnits(value *big.Rat, scale int, mode *RoundingMode) (int64, error) {
+	factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(factor))
+
+	if mode == nil {
+		if !scaled.IsInt() {
+			return 0, ErrPricePrecision
+		}
+		return ratToInt64(scaled.Num())
+	}
+
+	return roundRat(scaled, *mode)
+}
+
+func roundRat(value *big.Rat, mode RoundingMode) (int64, error) {
+	if value.IsInt() {
+		return ratToInt64(value.Num())
+	}
+
+	quo, rem := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	sign := int64(value.Sign())
+
+	// Compare the discarded fraction against one half: twice the remainder
+	// against the denominator.
+	half := new(big.Int).Abs(rem)
+	half.Lsh(half, 1)
+	cmpHalf := half.Cmp(value.Denom())
+
+	awayFromZero := false
+	switch mode {
+	case RoundHalfUp:
+		awayFromZero = cmpHalf >= 0
+	case RoundHalfDown:
+		awayFromZero = cmpHalf > 0
+	case RoundHalfEven:
+		awayFromZero = cmpHalf > 0 || (cmpHalf == 0 && quo.Bit(0) == 1)
+	case RoundDown:
+		awayFromZero = false
+	case RoundUp:
+		awayFromZero = true
+	case RoundFloor:
+		awayFromZero = sign < 0
+	case RoundCeiling:
+		awayFromZero = sign > 0
+	}
+
+	if awayFromZero {
+		quo.Add(quo, big.NewInt(sign))
+	}
+
+	return ratToInt64(quo)
+}
+
+func ratToInt64(v *big.Int) (int64, error) {
+	if !v.IsInt64() {
+		return 0, ErrPriceOverflow
+	}
+	return v.Int64(), nil
 }
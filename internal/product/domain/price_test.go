@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPriceLimits(t *testing.T) {
+	cases := []struct {
+		amount, currency string
+		want             error
+	}{
+		{"99999999.99", "USD", nil},
+		{"100000000.00", "USD", ErrPriceTooLarge},
+		{"100000000", "USD", ErrPriceTooLarge},
+		{"99999999", "JPY", nil},
+		{"100000000", "JPY", ErrPriceTooLarge},
+		{"0.01", "USD", nil},
+		{"0", "USD", ErrInvalidPrice},
+		{"99999999999999999999", "USD", ErrPriceOverflow},
+	}
+	for _, c := range cases {
+		_, err := ParsePrice(c.amount, c.currency)
+		if !errors.Is(err, c.want) {
+			t.Errorf("ParsePrice(%q, %s): got %v, want %v", c.amount, c.currency, err, c.want)
+		}
+	}
+
+	for _, c := range []struct {
+		minor    int64
+		currency string
+		want     error
+	}{
+		{9999999999, "USD", nil},
+		{10000000000, "USD", ErrPriceTooLarge},
+		{99999999, "KRW", nil},
+		{100000000, "KRW", ErrPriceTooLarge},
+	} {
+		_, err := NewPrice(c.minor, c.currency)
+		if !errors.Is(err, c.want) {
+			t.Errorf("NewPrice(%d, %s): got %v, want %v", c.minor, c.currency, err, c.want)
+		}
+	}
+
+	rounded, err := ParsePriceRounded("99999999.995", "USD", RoundHalfUp)
+	if !errors.Is(err, ErrPriceTooLarge) {
+		t.Errorf("ParsePriceRounded rounding past the limit: got %v, %v; want ErrPriceTooLarge", rounded, err)
+	}
+}
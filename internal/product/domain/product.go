@@ -19,19 +19,31 @@ type Product struct {
 	Description string
 	Price       Price
 	Stock       int
+	Reserved    int
 	Category    string
-	Active      bool
+	Status      ProductStatus
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	// CreatedBy and UpdatedBy are the IDs of the users who created and last
+	// changed the product; empty when the change was not made by a user.
+	CreatedBy string
+	UpdatedBy string
+	// Version is incremented on every successful save and is used for
+	// optimistic concurrency control.
+	Version int64
+	// DeletedAt is set while the product is soft-deleted, by the user in
+	// DeletedBy; deleted products are hidden until restored or purged.
+	DeletedAt *time.Time
+	DeletedBy string
 }
 
-func NewProduct(name, description string, price float64, stock int, category string) (*Product, error) {
+// NewProduct creates a draft product.
+func NewProduct(name, description string, price Price, stock int, category, createdBy string) (*Product, error) {
 	if err := validateName(name); err != nil {
 		return nil, err
 	}
 
-	priceVO, err := NewPrice(price)
-	if err != nil {
+	if err := validatePrice(price); err != nil {
 		return nil, err
 	}
 
@@ -45,22 +57,28 @@ func NewProduct(name, description string, price float64, stock int, category str
 		ID:          uuid.New().String(),
 		Name:        name,
 		Description: description,
-		Price:       priceVO,
+		Price:       price,
 		Stock:       stock,
 		Category:    category,
-		Active:      true,
+		Status:      StatusDraft,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		CreatedBy:   createdBy,
+		UpdatedBy:   createdBy,
+		Version:     1,
 	}, nil
 }
 
-func (p *Product) Update(name, description string, price float64, stock int, category string) error {
+func (p *Product) Update(name, description string, price Price, stock int, category, updatedBy string) error {
+	if p.Status == StatusArchived {
+		return ErrProductArchived
+	}
+
 	if err := validateName(name); err != nil {
 		return err
 	}
 
-	priceVO, err := NewPrice(price)
-	if err != nil {
+	if err := validatePrice(price); err != nil {
 		return err
 	}
 
@@ -68,29 +86,34 @@ func (p *Product) Update(name, description string, price float64, stock int, cat
 		return err
 	}
 
+	if stock < p.Reserved {
+		return ErrStockReserved
+	}
+
 	p.Name = name
 	p.Description = description
-	p.Price = priceVO
+	p.Price = price
 	p.Stock = stock
 	p.Category = category
 	p.UpdatedAt = time.Now()
+	p.UpdatedBy = updatedBy
 
 	return nil
 }
 
-func (p *Product) Deactivate() {
-	p.Active = false
-	p.UpdatedAt = time.Now()
+// Activate puts the product on sale.
+func (p *Product) Activate(updatedBy string) error {
+	return p.TransitionTo(StatusActive, updatedBy)
 }
 
-func (p *Product) Activate() {
-	p.Active = true
-	p.UpdatedAt = time.Now()
+// Deactivate takes an active product off sale.
+func (p *Product) Deactivate(updatedBy string) error {
+	return p.TransitionTo(StatusDiscontinued, updatedBy)
 }
 
 func (p *Product) ReduceStock(quantity int) error {
-	if p.Stock < quantity {
-		return errors.New("insufficient stock")
+	if p.Available() < quantity {
+		return ErrInsufficientStock
 	}
 	p.Stock -= quantity
 	p.UpdatedAt = time.Now()
@@ -113,6 +136,13 @@ func validateName(name string) error {
 	return nil
 }
 
+func validatePrice(price Price) error {
+	if !price.IsPositive() {
+		return ErrInvalidPrice
+	}
+	return nil
+}
+
 func validateStock(stock int) error {
 	if stock < 0 {
 		return ErrInvalidStock
@@ -1,19 +1,120 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// ProductRepository stores products. Soft-deleted products are left out of
+// every lookup unless stated otherwise; Update saves deletion and
+// restoration like any other change.
 type ProductRepository interface {
 	Create(ctx context.Context, product *Product) error
 	FindByID(ctx context.Context, id string) (*Product, error)
+	// FindByIDIncludingDeleted also finds a soft-deleted product.
+	FindByIDIncludingDeleted(ctx context.Context, id string) (*Product, error)
 	FindAll(ctx context.Context, filters ProductFilters) ([]*Product, error)
+	// Count returns how many products match the filters, ignoring Sort,
+	// After, Limit and Offset.
+	Count(ctx context.Context, filters ProductFilters) (int, error)
 	Update(ctx context.Context, product *Product) error
-	Delete(ctx context.Context, id string) error
+	// Purge permanently removes the products soft-deleted before the given
+	// time and returns how many there were.
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
+	// ExistsByName also considers soft-deleted products, which keep their
+	// name until purged.
 	ExistsByName(ctx context.Context, name string) (bool, error)
+	// Search returns one page of the products matching a full-text query,
+	// best match first, and how many products match in total. Products
+	// ranked equally are listed newest first.
+	Search(ctx context.Context, search ProductSearch) ([]*ProductMatch, int, error)
 }
 
+// ProductFilters select a page of products. Every set filter must match.
+// A page starts either Offset rows in or, for keyset pagination, right
+// after the After cursor.
 type ProductFilters struct {
-	Category string
-	Active   *bool
-	Limit    int
-	Offset   int
+	// Categories matches any of the listed categories.
+	Categories []string
+	Currency   string
+	// Active matches products whose status is, or is not, active.
+	Active *bool
+	// Statuses matches any of the listed lifecycle statuses.
+	Statuses []ProductStatus
+	// IncludeDeleted lists soft-deleted products too.
+	IncludeDeleted bool
+	// MinPrice and MaxPrice are inclusive decimal amounts, compared without
+	// regard to currency.
+	MinPrice string
+	MaxPrice string
+	// MinStock and MaxStock are inclusive.
+	MinStock *int
+	MaxStock *int
+	// CreatedFrom, CreatedTo, UpdatedFrom and UpdatedTo are inclusive.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	// NamePrefix and NameContains match the name case-insensitively.
+	NamePrefix   string
+	NameContains string
+	// Sort defaults to DefaultProductSort.
+	Sort   []ProductSort
+	After  *ProductCursor
+	Limit  int
+	Offset int
+}
+
+// ProductSortField is a column products can be ordered by.
+type ProductSortField string
+
+const (
+	SortByName      ProductSortField = "name"
+	SortByPrice     ProductSortField = "price"
+	SortByStock     ProductSortField = "stock"
+	SortByCreatedAt ProductSortField = "created_at"
+	SortByUpdatedAt ProductSortField = "updated_at"
+)
+
+// ProductSort is one ordering term. Names are ordered case-insensitively by
+// code point; prices by amount, without regard to currency.
+type ProductSort struct {
+	Field ProductSortField
+	Desc  bool
+}
+
+// DefaultProductSort lists the newest products first.
+var DefaultProductSort = []ProductSort{{Field: SortByCreatedAt, Desc: true}}
+
+// SortOrder returns the ordering terms of the listing. Products equal on
+// every term are ordered by ID in the direction of the last term, so that
+// the order is total and pages never overlap.
+func (f ProductFilters) SortOrder() []ProductSort {
+	if len(f.Sort) == 0 {
+		return DefaultProductSort
+	}
+	return f.Sort
+}
+
+// ProductCursor is a position in the listing order: the sortable fields and
+// ID of the last product of the previous page.
+type ProductCursor struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Price     string    `json:"price,omitempty"`
+	Stock     int       `json:"stock,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CursorOf returns the position of product in the listing order.
+func CursorOf(product *Product) ProductCursor {
+	return ProductCursor{
+		ID:        product.ID,
+		Name:      product.Name,
+		Price:     product.Price.String(),
+		Stock:     product.Stock,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+	}
 }
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidReservation    = errors.New("reserved quantity and time to live must be positive")
+	ErrReservationNotPending = errors.New("reservation is no longer pending")
+	ErrReservationExpired    = errors.New("reservation has expired")
+	ErrProductNotOnSale      = errors.New("product is not on sale")
+	ErrStockReserved         = errors.New("stock cannot be less than the reserved quantity")
+)
+
+// ReservationStatus is the state of a stock reservation. Only pending
+// reservations hold stock; the others are final.
+type ReservationStatus string
+
+const (
+	ReservationPending   ReservationStatus = "pending"
+	ReservationConfirmed ReservationStatus = "confirmed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// StockReservation holds units of a product, e.g. during a checkout, so that
+// nobody else can buy them until it is confirmed as a sale, released, or
+// expires.
+type StockReservation struct {
+	ID        string
+	ProductID string
+	Quantity  int
+	Status    ReservationStatus
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	// CreatedBy is the ID of the user who reserved the stock; empty when the
+	// reservation was not made by a user.
+	CreatedBy string
+	// ResolvedAt is when the reservation stopped being pending.
+	ResolvedAt *time.Time
+}
+
+// NewStockReservation returns a pending reservation of quantity units that
+// expires after ttl.
+func NewStockReservation(productID string, quantity int, ttl time.Duration, createdBy string) (*StockReservation, error) {
+	if quantity <= 0 || ttl <= 0 {
+		return nil, ErrInvalidReservation
+	}
+
+	now := time.Now()
+
+	return &StockReservation{
+		ID:        uuid.New().String(),
+		ProductID: productID,
+		Quantity:  quantity,
+		Status:    ReservationPending,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+		CreatedBy: createdBy,
+	}, nil
+}
+
+// IsExpired reports whether the reservation can no longer be confirmed at t.
+func (r *StockReservation) IsExpired(t time.Time) bool {
+	return !t.Before(r.ExpiresAt)
+}
+
+// Sale returns the ledger movement of the confirmed reservation, which left
+// stockAfter units in stock.
+func (r *StockReservation) Sale(stockAfter int, confirmedBy string) *StockMovement {
+	return &StockMovement{
+		ID:         uuid.New().String(),
+		ProductID:  r.ProductID,
+		Quantity:   -r.Quantity,
+		Reason:     ReasonSale,
+		StockAfter: stockAfter,
+		Note:       fmt.Sprintf("reservation %s", r.ID),
+		CreatedAt:  time.Now(),
+		CreatedBy:  confirmedBy,
+	}
+}
+
+// Available is the stock not held by pending reservations, which Reserved
+// counts.
+func (p *Product) Available() int {
+	return p.Stock - p.Reserved
+}
+
+// StockReservationRepository keeps reservations and the reserved count of
+// products in step. Every method changes both with conditional statements,
+// so concurrent callers can never reserve more than is available nor
+// resolve a reservation twice; each change also increments the product's
+// version.
+type StockReservationRepository interface {
+	// Reserve holds the reserved units of an active product and stores the
+	// reservation. It fails with ErrNotFound when the product does not exist
+	// or is deleted, ErrProductNotOnSale when it is not active and
+	// ErrInsufficientStock when fewer units are available.
+	Reserve(ctx context.Context, reservation *StockReservation) error
+	FindByID(ctx context.Context, id string) (*StockReservation, error)
+	// Confirm turns a pending reservation into a sale: its units leave the
+	// stock along with the reserved count. It returns the stock left, or
+	// ErrReservationExpired when the reservation expired by at,
+	// ErrReservationNotPending when it was already resolved and
+	// ErrProductNotOnSale when the product was deleted since.
+	Confirm(ctx context.Context, reservation *StockReservation, at time.Time) (int, error)
+	// Release makes the units of a pending reservation available again and
+	// resolves it with status, ReservationReleased or ReservationExpired. It
+	// fails with ErrReservationNotPending when the reservation was already
+	// resolved.
+	Release(ctx context.Context, reservation *StockReservation, status ReservationStatus, at time.Time) error
+	// FindExpired lists up to limit pending reservations expired by at,
+	// oldest expiry first.
+	FindExpired(ctx context.Context, at time.Time, limit int) ([]*StockReservation, error)
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxSearchTerms bounds the number of words in a search query.
+const MaxSearchTerms = 10
+
+// ProductSearch is a full-text query over product names and descriptions.
+type ProductSearch struct {
+	// Terms are lowercase words, as returned by SearchTerms. A product
+	// matches when every term starts a word of its name or description.
+	Terms  []string
+	Limit  int
+	Offset int
+}
+
+// ProductMatch is a product found by a search. Rank is higher for better
+// matches, and a match in the name outranks one in the description; its
+// scale depends on the backend, so ranks only compare within one result.
+type ProductMatch struct {
+	Product *Product
+	Rank    float64
+}
+
+// SearchTerms splits a query into distinct lowercase words. Anything but
+// letters and digits separates words, so the terms are safe to embed in
+// every backend's full-text syntax.
+func SearchTerms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range SearchWords(query) {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// SearchWords splits text into lowercase words the way searches see it.
+func SearchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("status must be one of draft, active, discontinued, archived")
+	ErrInvalidTransition = errors.New("product cannot move to that status")
+	ErrProductArchived   = errors.New("archived products cannot be changed")
+)
+
+// ProductStatus is a stage of the product lifecycle:
+//
+//	draft -> active <-> discontinued -> archived
+//
+// Drafts can also be archived directly; archived products are final.
+type ProductStatus string
+
+const (
+	StatusDraft        ProductStatus = "draft"
+	StatusActive       ProductStatus = "active"
+	StatusDiscontinued ProductStatus = "discontinued"
+	StatusArchived     ProductStatus = "archived"
+)
+
+var transitions = map[ProductStatus][]ProductStatus{
+	StatusDraft:        {StatusActive, StatusArchived},
+	StatusActive:       {StatusDiscontinued},
+	StatusDiscontinued: {StatusActive, StatusArchived},
+	StatusArchived:     {},
+}
+
+// ParseProductStatus validates a status name.
+func ParseProductStatus(s string) (ProductStatus, error) {
+	status := ProductStatus(s)
+	if _, ok := transitions[status]; !ok {
+		return "", ErrInvalidStatus
+	}
+	return status, nil
+}
+
+// AllowedTransitions lists the statuses the product can move to next.
+func (p *Product) AllowedTransitions() []ProductStatus {
+	return append([]ProductStatus{}, transitions[p.Status]...)
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving to status.
+func (p *Product) CanTransitionTo(status ProductStatus) bool {
+	for _, next := range transitions[p.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo moves the product to status if the lifecycle allows it.
+func (p *Product) TransitionTo(status ProductStatus, updatedBy string) error {
+	if !p.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, p.Status, status)
+	}
+
+	p.Status = status
+	p.UpdatedAt = time.Now()
+	p.UpdatedBy = updatedBy
+	return nil
+}
+
+// IsActive reports whether the product is on sale.
+func (p *Product) IsActive() bool {
+	return p.Status == StatusActive
+}
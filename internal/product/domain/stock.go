@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidReason     = errors.New("reason must be one of sale, restock, damage, correction")
+	ErrInvalidQuantity   = errors.New("quantity does not match the reason")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// MovementReason says why the stock of a product changed.
+type MovementReason string
+
+const (
+	// ReasonInitial records the stock a product was created with. It is
+	// never given by users.
+	ReasonInitial    MovementReason = "initial"
+	ReasonSale       MovementReason = "sale"
+	ReasonRestock    MovementReason = "restock"
+	ReasonDamage     MovementReason = "damage"
+	ReasonCorrection MovementReason = "correction"
+)
+
+// ParseMovementReason validates a reason a user can adjust stock for.
+func ParseMovementReason(s string) (MovementReason, error) {
+	switch reason := MovementReason(s); reason {
+	case ReasonSale, ReasonRestock, ReasonDamage, ReasonCorrection:
+		return reason, nil
+	}
+	return "", ErrInvalidReason
+}
+
+// validQuantity reports whether a signed change of quantity units fits the
+// reason: sales and damage take stock out, restocks bring it in, and
+// corrections go either way.
+func (r MovementReason) validQuantity(quantity int) bool {
+	switch r {
+	case ReasonSale, ReasonDamage:
+		return quantity < 0
+	case ReasonRestock:
+		return quantity > 0
+	case ReasonInitial:
+		return quantity >= 0
+	}
+	return quantity != 0
+}
+
+// StockMovement is an immutable entry of the stock ledger. The quantities of
+// a product's movements add up to its stock.
+type StockMovement struct {
+	ID        string
+	ProductID string
+	// Quantity is the signed change: negative when stock went out.
+	Quantity int
+	Reason   MovementReason
+	// StockAfter is the product's stock once the movement was applied.
+	StockAfter int
+	Note       string
+	CreatedAt  time.Time
+	// CreatedBy is the ID of the user who moved the stock; empty when the
+	// change was not made by a user.
+	CreatedBy string
+}
+
+// NewStockMovement records that the stock of product changed by quantity to
+// its current value.
+func NewStockMovement(product *Product, quantity int, reason MovementReason, note, createdBy string) (*StockMovement, error) {
+	if !reason.validQuantity(quantity) {
+		return nil, fmt.Errorf("%w: %s of %d", ErrInvalidQuantity, reason, quantity)
+	}
+
+	return &StockMovement{
+		ID:         uuid.New().String(),
+		ProductID:  product.ID,
+		Quantity:   quantity,
+		Reason:     reason,
+		StockAfter: product.Stock,
+		Note:       note,
+		CreatedAt:  time.Now(),
+		CreatedBy:  createdBy,
+	}, nil
+}
+
+// AdjustStock changes the stock by the signed quantity and returns the
+// movement to record.
+func (p *Product) AdjustStock(quantity int, reason MovementReason, note, adjustedBy string) (*StockMovement, error) {
+	if p.Status == StatusArchived {
+		return nil, ErrProductArchived
+	}
+
+	if reason == ReasonInitial || !reason.validQuantity(quantity) {
+		return nil, fmt.Errorf("%w: %s of %d", ErrInvalidQuantity, reason, quantity)
+	}
+
+	var err error
+	if quantity < 0 {
+		err = p.ReduceStock(-quantity)
+	} else {
+		err = p.IncreaseStock(quantity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.UpdatedBy = adjustedBy
+
+	return NewStockMovement(p, quantity, reason, note, adjustedBy)
+}
+
+// StockMovementRepository is the append-only stock ledger.
+type StockMovementRepository interface {
+	Create(ctx context.Context, movement *StockMovement) error
+	// FindAll lists a product's movements, newest first.
+	FindAll(ctx context.Context, filters StockMovementFilters) ([]*StockMovement, error)
+	// Count returns how many movements a product has.
+	Count(ctx context.Context, productID string) (int, error)
+	// Balance adds up the quantities of a product's movements.
+	Balance(ctx context.Context, productID string) (int, error)
+}
+
+// StockMovementFilters select a page of one product's movements.
+type StockMovementFilters struct {
+	ProductID string
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,51 @@
+package infra
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/product/application"
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/product/infra/mssql"
+	"go-architecture/internal/product/infra/postgres"
+	"go-architecture/internal/product/infra/sqlite"
+	"go-architecture/internal/shared/database"
+)
+
+// Repositories are the product persistence adapters for one backend.
+type Repositories struct {
+	Products          domain.ProductRepository
+	StockMovements    domain.StockMovementRepository
+	StockReservations domain.StockReservationRepository
+	UnitOfWork        application.UnitOfWork
+}
+
+// NewRepositories builds the adapters matching the configured database
+// driver.
+func NewRepositories(driver string, db *sqlx.DB) (*Repositories, error) {
+	switch driver {
+	case database.DriverMSSQL:
+		return &Repositories{
+			Products:          mssql.NewProductRepository(db),
+			StockMovements:    mssql.NewStockMovementRepository(db),
+			StockReservations: mssql.NewStockReservationRepository(db),
+			UnitOfWork:        mssql.NewUnitOfWork(db),
+		}, nil
+	case database.DriverPostgres:
+		return &Repositories{
+			Products:          postgres.NewProductRepository(db),
+			StockMovements:    postgres.NewStockMovementRepository(db),
+			StockReservations: postgres.NewStockReservationRepository(db),
+			UnitOfWork:        postgres.NewUnitOfWork(db),
+		}, nil
+	case database.DriverSQLite:
+		return &Repositories{
+			Products:          sqlite.NewProductRepository(db),
+			StockMovements:    sqlite.NewStockMovementRepository(db),
+			StockReservations: sqlite.NewStockReservationRepository(db),
+			UnitOfWork:        sqlite.NewUnitOfWork(db),
+		}, nil
+	default:
+		return nil, fmt.Errorf("product: no repositories for database driver %q", driver)
+	}
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+)
+
+// etag renders a product version as a strong entity tag.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseETag extracts the version from an entity tag, accepting weak tags
+// (W/"3") as well as strong ones ("3").
+func parseETag(tag string) (int64, bool) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
+// matchesETag reports whether an If-None-Match style header lists the given
+// version.
+func matchesETag(header string, version int64) bool {
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimSpace(tag) == "*" {
+			return true
+		}
+		if v, ok := parseETag(tag); ok && v == version {
+			return true
+		}
+	}
+	return false
+}
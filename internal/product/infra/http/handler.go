@@ -1,9 +1,15 @@
 package http
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"go-architecture/internal/product/application"
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/shared/errors"
+	sharedhttp "go-architecture/internal/shared/http"
 	"go-architecture/internal/shared/logger"
+	"go-architecture/internal/shared/patch"
 )
 
 type ProductHandler struct {
@@ -28,7 +34,7 @@ func (h *ProductHandler) Create(c *fiber.Ctx) error {
 		})
 	}
 
-	product, err := h.service.Create(c.Context(), dto)
+	product, err := h.service.Create(c.UserContext(), dto)
 	if err != nil {
 		return err
 	}
@@ -41,11 +47,16 @@ func (h *ProductHandler) Create(c *fiber.Ctx) error {
 func (h *ProductHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	product, err := h.service.GetByID(c.Context(), id)
+	product, err := h.service.GetByID(c.UserContext(), id)
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, etag(product.Version))
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" && matchesETag(ifNoneMatch, product.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return c.JSON(fiber.Map{
 		"data": product,
 	})
@@ -61,14 +72,64 @@ func (h *ProductHandler) GetAll(c *fiber.Ctx) error {
 		})
 	}
 
-	products, err := h.service.GetAll(c.Context(), filters)
+	page, err := h.service.GetAll(c.UserContext(), filters)
+	if err != nil {
+		return err
+	}
+
+	sharedhttp.SetPaginationLinks(c, sharedhttp.Page{
+		Total:      page.Total,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		HasMore:    page.HasMore,
+		Cursor:     filters.Cursor,
+		NextCursor: page.NextCursor,
+	})
+
+	response := fiber.Map{
+		"data":     page.Products,
+		"count":    len(page.Products),
+		"total":    page.Total,
+		"limit":    page.Limit,
+		"offset":   page.Offset,
+		"has_more": page.HasMore,
+	}
+	if page.NextCursor != "" {
+		response["next_cursor"] = page.NextCursor
+	}
+
+	return c.JSON(response)
+}
+
+func (h *ProductHandler) Search(c *fiber.Ctx) error {
+	var search application.ProductSearchDTO
+
+	if err := c.QueryParser(&search); err != nil {
+		h.log.Error("Failed to parse query parameters", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	page, err := h.service.Search(c.UserContext(), search)
 	if err != nil {
 		return err
 	}
 
+	sharedhttp.SetPaginationLinks(c, sharedhttp.Page{
+		Total:   page.Total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+		HasMore: page.HasMore,
+	})
+
 	return c.JSON(fiber.Map{
-		"data":  products,
-		"count": len(products),
+		"data":     page.Results,
+		"count":    len(page.Results),
+		"total":    page.Total,
+		"limit":    page.Limit,
+		"offset":   page.Offset,
+		"has_more": page.HasMore,
 	})
 }
 
@@ -83,22 +144,188 @@ func (h *ProductHandler) Update(c *fiber.Ctx) error {
 		})
 	}
 
-	product, err := h.service.Update(c.Context(), id, dto)
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	product, err := h.service.Update(c.UserContext(), id, dto, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, etag(product.Version))
+	return c.JSON(fiber.Map{
+		"data": product,
+	})
+}
+
+// Patch applies an application/merge-patch+json or application/json-patch+json
+// document to a product.
+func (h *ProductHandler) Patch(c *fiber.Ctx) error {
+	id := c.Params("id")
+	c.Set("Accept-Patch", patch.MergePatchType+", "+patch.JSONPatchType)
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	product, err := h.service.Patch(c.UserContext(), id, strings.ToLower(strings.TrimSpace(mediaType)), c.Body(), expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, etag(product.Version))
+	return c.JSON(fiber.Map{
+		"data": product,
+	})
+}
+
+// Transition moves a product to the lifecycle status in the request body.
+func (h *ProductHandler) Transition(c *fiber.Ctx) error {
+	var dto application.TransitionProductDTO
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	return h.transition(c, dto)
+}
+
+// Activate puts a product on sale.
+func (h *ProductHandler) Activate(c *fiber.Ctx) error {
+	return h.transition(c, application.TransitionProductDTO{Status: string(domain.StatusActive)})
+}
+
+// Deactivate takes a product off sale.
+func (h *ProductHandler) Deactivate(c *fiber.Ctx) error {
+	return h.transition(c, application.TransitionProductDTO{Status: string(domain.StatusDiscontinued)})
+}
+
+func (h *ProductHandler) transition(c *fiber.Ctx, dto application.TransitionProductDTO) error {
+	id := c.Params("id")
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	product, err := h.service.Transition(c.UserContext(), id, dto, expectedVersion)
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, etag(product.Version))
 	return c.JSON(fiber.Map{
 		"data": product,
 	})
 }
 
+// AdjustStock moves stock in or out of a product and returns the recorded
+// movement with the updated product.
+func (h *ProductHandler) AdjustStock(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var dto application.StockAdjustmentDTO
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.service.AdjustStock(c.UserContext(), id, dto, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, etag(result.Product.Version))
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": result,
+	})
+}
+
+// StockHistory lists the stock movements of a product, newest first.
+func (h *ProductHandler) StockHistory(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var filters application.StockHistoryFiltersDTO
+	if err := c.QueryParser(&filters); err != nil {
+		h.log.Error("Failed to parse query parameters", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	page, err := h.service.StockHistory(c.UserContext(), id, filters)
+	if err != nil {
+		return err
+	}
+
+	sharedhttp.SetPaginationLinks(c, sharedhttp.Page{
+		Total:   page.Total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+		HasMore: page.HasMore,
+	})
+
+	return c.JSON(fiber.Map{
+		"data":           page.Movements,
+		"count":          len(page.Movements),
+		"total":          page.Total,
+		"limit":          page.Limit,
+		"offset":         page.Offset,
+		"has_more":       page.HasMore,
+		"stock":          page.Stock,
+		"ledger_balance": page.LedgerBalance,
+	})
+}
+
 func (h *ProductHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.service.Delete(c.Context(), id); err != nil {
+	if err := h.service.Delete(c.UserContext(), id); err != nil {
 		return err
 	}
 
 	return c.Status(fiber.StatusNoContent).Send(nil)
 }
+
+// Restore brings back a soft-deleted product.
+func (h *ProductHandler) Restore(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	product, err := h.service.Restore(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, etag(product.Version))
+	return c.JSON(fiber.Map{
+		"data": product,
+	})
+}
+
+// ifMatchVersion reads the If-Match header. A missing header or "*" means the
+// update is unconditional.
+func ifMatchVersion(c *fiber.Ctx) (*int64, error) {
+	header := c.Get(fiber.HeaderIfMatch)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	version, ok := parseETag(header)
+	if !ok {
+		return nil, errors.NewPreconditionFailedError("If-Match must be a single product ETag")
+	}
+	return &version, nil
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go-architecture/internal/product/application"
+	"go-architecture/internal/shared/logger"
+)
+
+// ReservationHandler serves the stock reservations of a product.
+type ReservationHandler struct {
+	service *application.ReservationService
+	log     *logger.Logger
+}
+
+func NewReservationHandler(service *application.ReservationService, log *logger.Logger) *ReservationHandler {
+	return &ReservationHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+func (h *ReservationHandler) Reserve(c *fiber.Ctx) error {
+	var dto application.ReserveStockDTO
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	reservation, err := h.service.Reserve(c.UserContext(), c.Params("id"), dto)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": reservation,
+	})
+}
+
+func (h *ReservationHandler) Get(c *fiber.Ctx) error {
+	reservation, err := h.service.Get(c.UserContext(), c.Params("id"), c.Params("reservation_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": reservation,
+	})
+}
+
+// Confirm sells the reserved stock.
+func (h *ReservationHandler) Confirm(c *fiber.Ctx) error {
+	reservation, err := h.service.Confirm(c.UserContext(), c.Params("id"), c.Params("reservation_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": reservation,
+	})
+}
+
+// Release makes the reserved stock available again.
+func (h *ReservationHandler) Release(c *fiber.Ctx) error {
+	reservation, err := h.service.Release(c.UserContext(), c.Params("id"), c.Params("reservation_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": reservation,
+	})
+}
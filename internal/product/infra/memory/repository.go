@@ -0,0 +1,363 @@
+// Package memory is an in-process ProductRepository for application-layer
+// tests. It honours the same contract as the SQL backends (see repotest) but
+// keeps nothing across restarts.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-architecture/internal/product/domain"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+// ProductRepository stores copies of the products it is given, so callers
+// never share state with the store. It is safe for concurrent use.
+type ProductRepository struct {
+	mu       sync.RWMutex
+	products map[string]domain.Product
+}
+
+func NewProductRepository() *ProductRepository {
+	return &ProductRepository{products: make(map[string]domain.Product)}
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.products[product.ID]; ok {
+		return fmt.Errorf("memory: product %s already exists", product.ID)
+	}
+
+	r.products[product.ID] = *product
+	return nil
+}
+
+func (r *ProductRepository) FindByID(ctx context.Context, id string) (*domain.Product, error) {
+	product, err := r.FindByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsDeleted() {
+		return nil, apperrors.ErrNotFound
+	}
+
+	return product, nil
+}
+
+func (r *ProductRepository) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+
+	return &product, nil
+}
+
+// FindAll mirrors productquery.FindProducts: the filters' sort order with
+// ties broken by ID, then offset and limit applied like SQL LIMIT/OFFSET.
+func (r *ProductRepository) FindAll(ctx context.Context, filters domain.ProductFilters) ([]*domain.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order := filters.SortOrder()
+	products := make([]*domain.Product, 0, len(r.products))
+	for _, stored := range r.products {
+		if !matches(&stored, filters) {
+			continue
+		}
+		if filters.After != nil && compare(domain.CursorOf(&stored), *filters.After, order) <= 0 {
+			continue
+		}
+		product := stored
+		products = append(products, &product)
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		return compare(domain.CursorOf(products[i]), domain.CursorOf(products[j]), order) < 0
+	})
+
+	return paginate(products, filters.Limit, filters.Offset), nil
+}
+
+func (r *ProductRepository) Count(ctx context.Context, filters domain.ProductFilters) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, stored := range r.products {
+		if matches(&stored, filters) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.products[product.ID]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+
+	if stored.Version != product.Version {
+		return apperrors.ErrConcurrencyConflict
+	}
+
+	product.Version++
+	r.products[product.ID] = *product
+	return nil
+}
+
+func (r *ProductRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, product := range r.products {
+		if product.IsDeleted() && product.DeletedAt.Before(deletedBefore) {
+			delete(r.products, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (r *ProductRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, product := range r.products {
+		if product.Name == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Search ranks each matching product by its words starting with a term: ten
+// points per name word, one per description word.
+func (r *ProductRepository) Search(ctx context.Context, search domain.ProductSearch) ([]*domain.ProductMatch, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []*domain.ProductMatch
+	for _, stored := range r.products {
+		if stored.IsDeleted() {
+			continue
+		}
+		rank, ok := score(&stored, search.Terms)
+		if !ok {
+			continue
+		}
+		product := stored
+		matches = append(matches, &domain.ProductMatch{Product: &product, Rank: rank})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Rank != matches[j].Rank {
+			return matches[i].Rank > matches[j].Rank
+		}
+		return compare(domain.CursorOf(matches[i].Product), domain.CursorOf(matches[j].Product), domain.DefaultProductSort) < 0
+	})
+
+	total := len(matches)
+	if search.Offset >= total {
+		return matches[:0], total, nil
+	}
+	matches = matches[search.Offset:]
+	if search.Limit < len(matches) {
+		matches = matches[:search.Limit]
+	}
+	return matches, total, nil
+}
+
+// snapshot and restore let the unit of work roll back a failed transaction.
+func (r *ProductRepository) snapshot() map[string]domain.Product {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := make(map[string]domain.Product, len(r.products))
+	for id, product := range r.products {
+		products[id] = product
+	}
+	return products
+}
+
+func (r *ProductRepository) restore(products map[string]domain.Product) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.products = products
+}
+
+// matches applies every filter except the cursor.
+func matches(product *domain.Product, filters domain.ProductFilters) bool {
+	if product.IsDeleted() && !filters.IncludeDeleted {
+		return false
+	}
+	if len(filters.Categories) > 0 && !contains(filters.Categories, product.Category) {
+		return false
+	}
+	if filters.Currency != "" && product.Price.Currency().Code() != filters.Currency {
+		return false
+	}
+	if filters.Active != nil && product.IsActive() != *filters.Active {
+		return false
+	}
+	if len(filters.Statuses) > 0 && !containsStatus(filters.Statuses, product.Status) {
+		return false
+	}
+	if filters.MinPrice != "" && compareAmounts(product.Price.String(), filters.MinPrice) < 0 {
+		return false
+	}
+	if filters.MaxPrice != "" && compareAmounts(product.Price.String(), filters.MaxPrice) > 0 {
+		return false
+	}
+	if filters.MinStock != nil && product.Stock < *filters.MinStock {
+		return false
+	}
+	if filters.MaxStock != nil && product.Stock > *filters.MaxStock {
+		return false
+	}
+	if !within(product.CreatedAt, filters.CreatedFrom, filters.CreatedTo) ||
+		!within(product.UpdatedAt, filters.UpdatedFrom, filters.UpdatedTo) {
+		return false
+	}
+	name := strings.ToLower(product.Name)
+	if filters.NamePrefix != "" && !strings.HasPrefix(name, strings.ToLower(filters.NamePrefix)) {
+		return false
+	}
+	return filters.NameContains == "" || strings.Contains(name, strings.ToLower(filters.NameContains))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStatus(statuses []domain.ProductStatus, status domain.ProductStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// within reports whether t lies in the inclusive range [from, to]; nil bounds
+// are open.
+func within(t time.Time, from, to *time.Time) bool {
+	return (from == nil || !t.Before(*from)) && (to == nil || !t.After(*to))
+}
+
+// compare orders a and b the way the listing does: by each sort term, then
+// by ID in the direction of the last term. It returns -1 when a is listed
+// first.
+func compare(a, b domain.ProductCursor, order []domain.ProductSort) int {
+	for _, s := range order {
+		var c int
+		switch s.Field {
+		case domain.SortByName:
+			c = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		case domain.SortByPrice:
+			c = compareAmounts(a.Price, b.Price)
+		case domain.SortByStock:
+			c = compareInts(a.Stock, b.Stock)
+		case domain.SortByUpdatedAt:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		default:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if s.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	c := strings.Compare(a.ID, b.ID)
+	if order[len(order)-1].Desc {
+		c = -c
+	}
+	return c
+}
+
+// compareAmounts compares decimal strings numerically, like the SQL
+// backends compare prices regardless of currency.
+func compareAmounts(a, b string) int {
+	x, _ := new(big.Rat).SetString(a)
+	y, _ := new(big.Rat).SetString(b)
+	if x == nil || y == nil {
+		return strings.Compare(a, b)
+	}
+	return x.Cmp(y)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// score reports whether every term starts a word of the product's name or
+// description, and how well the product matches.
+func score(product *domain.Product, terms []string) (float64, bool) {
+	name := domain.SearchWords(product.Name)
+	description := domain.SearchWords(product.Description)
+
+	rank := 0.0
+	for _, term := range terms {
+		hits := 10*prefixed(name, term) + prefixed(description, term)
+		if hits == 0 {
+			return 0, false
+		}
+		rank += float64(hits)
+	}
+	return rank, len(terms) > 0
+}
+
+func prefixed(words []string, term string) int {
+	n := 0
+	for _, word := range words {
+		if strings.HasPrefix(word, term) {
+			n++
+		}
+	}
+	return n
+}
+
+func paginate(products []*domain.Product, limit, offset int) []*domain.Product {
+	if offset >= len(products) {
+		return products[:0]
+	}
+	products = products[offset:]
+
+	if limit < len(products) {
+		products = products[:limit]
+	}
+	return products
+}
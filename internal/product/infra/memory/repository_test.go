@@ -0,0 +1,28 @@
+package memory_test
+
+import (
+	"testing"
+
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/product/infra/memory"
+	"go-architecture/internal/product/infra/repotest"
+)
+
+func TestProductRepository(t *testing.T) {
+	repotest.TestProductRepository(t, func(t *testing.T) domain.ProductRepository {
+		return memory.NewProductRepository()
+	})
+}
+
+func TestStockMovementRepository(t *testing.T) {
+	repotest.TestStockMovementRepository(t, func(t *testing.T) (domain.ProductRepository, domain.StockMovementRepository) {
+		return memory.NewProductRepository(), memory.NewStockMovementRepository()
+	})
+}
+
+func TestStockReservationRepository(t *testing.T) {
+	repotest.TestStockReservationRepository(t, func(t *testing.T) (domain.ProductRepository, domain.StockReservationRepository) {
+		products := memory.NewProductRepository()
+		return products, memory.NewStockReservationRepository(products)
+	})
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-architecture/internal/product/domain"
+)
+
+// StockMovementRepository keeps the ledger in insertion order. It is safe for
+// concurrent use.
+type StockMovementRepository struct {
+	mu        sync.RWMutex
+	movements []domain.StockMovement
+}
+
+func NewStockMovementRepository() *StockMovementRepository {
+	return &StockMovementRepository{}
+}
+
+func (r *StockMovementRepository) Create(ctx context.Context, movement *domain.StockMovement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.movements {
+		if stored.ID == movement.ID {
+			return fmt.Errorf("memory: stock movement %s already exists", movement.ID)
+		}
+	}
+
+	r.movements = append(r.movements, *movement)
+	return nil
+}
+
+// FindAll orders movements like the SQL backends: newest first, ties broken
+// by descending ID.
+func (r *StockMovementRepository) FindAll(ctx context.Context, filters domain.StockMovementFilters) ([]*domain.StockMovement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var movements []*domain.StockMovement
+	for _, stored := range r.movements {
+		if stored.ProductID != filters.ProductID {
+			continue
+		}
+		movement := stored
+		movements = append(movements, &movement)
+	}
+
+	sort.Slice(movements, func(i, j int) bool {
+		if c := movements[i].CreatedAt.Compare(movements[j].CreatedAt); c != 0 {
+			return c > 0
+		}
+		return strings.Compare(movements[i].ID, movements[j].ID) > 0
+	})
+
+	if filters.Offset >= len(movements) {
+		return movements[:0], nil
+	}
+	movements = movements[filters.Offset:]
+	if filters.Limit < len(movements) {
+		movements = movements[:filters.Limit]
+	}
+	return movements, nil
+}
+
+func (r *StockMovementRepository) Count(ctx context.Context, productID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, movement := range r.movements {
+		if movement.ProductID == productID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *StockMovementRepository) Balance(ctx context.Context, productID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	balance := 0
+	for _, movement := range r.movements {
+		if movement.ProductID == productID {
+			balance += movement.Quantity
+		}
+	}
+	return balance, nil
+}
+
+// snapshot and restore let the unit of work roll back a failed transaction.
+func (r *StockMovementRepository) snapshot() []domain.StockMovement {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]domain.StockMovement(nil), r.movements...)
+}
+
+func (r *StockMovementRepository) restore(movements []domain.StockMovement) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.movements = movements
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-architecture/internal/product/domain"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+// StockReservationRepository keeps reservations and changes the reserved
+// counts of the products in a ProductRepository, holding both locks so that
+// every change is atomic. It is safe for concurrent use.
+type StockReservationRepository struct {
+	mu           sync.Mutex
+	products     *ProductRepository
+	reservations map[string]domain.StockReservation
+}
+
+func NewStockReservationRepository(products *ProductRepository) *StockReservationRepository {
+	return &StockReservationRepository{
+		products:     products,
+		reservations: make(map[string]domain.StockReservation),
+	}
+}
+
+func (r *StockReservationRepository) Reserve(ctx context.Context, reservation *domain.StockReservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.products.mu.Lock()
+	defer r.products.mu.Unlock()
+
+	if _, ok := r.reservations[reservation.ID]; ok {
+		return fmt.Errorf("memory: stock reservation %s already exists", reservation.ID)
+	}
+
+	product, ok := r.products.products[reservation.ProductID]
+	switch {
+	case !ok || product.IsDeleted():
+		return apperrors.ErrNotFound
+	case !product.IsActive():
+		return domain.ErrProductNotOnSale
+	case product.Available() < reservation.Quantity:
+		return domain.ErrInsufficientStock
+	}
+
+	product.Reserved += reservation.Quantity
+	product.Version++
+	r.products.products[product.ID] = product
+	r.reservations[reservation.ID] = *reservation
+	return nil
+}
+
+func (r *StockReservationRepository) FindByID(ctx context.Context, id string) (*domain.StockReservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+
+	return &reservation, nil
+}
+
+func (r *StockReservationRepository) Confirm(ctx context.Context, reservation *domain.StockReservation, at time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.products.mu.Lock()
+	defer r.products.mu.Unlock()
+
+	stored, err := r.pending(reservation.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	product, ok := r.products.products[stored.ProductID]
+	if !ok {
+		return 0, apperrors.ErrNotFound
+	}
+	if product.IsDeleted() {
+		return 0, domain.ErrProductNotOnSale
+	}
+	if stored.IsExpired(at) {
+		return 0, domain.ErrReservationExpired
+	}
+	product.Stock -= stored.Quantity
+	product.Reserved -= stored.Quantity
+	product.Version++
+	r.products.products[product.ID] = product
+
+	r.resolve(stored, reservation, domain.ReservationConfirmed, at)
+	return product.Stock, nil
+}
+
+func (r *StockReservationRepository) Release(ctx context.Context, reservation *domain.StockReservation, status domain.ReservationStatus, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.products.mu.Lock()
+	defer r.products.mu.Unlock()
+
+	stored, err := r.pending(reservation.ID)
+	if err != nil {
+		return err
+	}
+
+	product, ok := r.products.products[stored.ProductID]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+	product.Reserved -= stored.Quantity
+	product.Version++
+	r.products.products[product.ID] = product
+
+	r.resolve(stored, reservation, status, at)
+	return nil
+}
+
+// pending returns the stored reservation if it is still pending.
+func (r *StockReservationRepository) pending(id string) (domain.StockReservation, error) {
+	stored, ok := r.reservations[id]
+	if !ok {
+		return stored, apperrors.ErrNotFound
+	}
+	if stored.Status != domain.ReservationPending {
+		return stored, domain.ErrReservationNotPending
+	}
+	return stored, nil
+}
+
+// resolve saves the resolved reservation and reports it to the caller.
+func (r *StockReservationRepository) resolve(stored domain.StockReservation, reservation *domain.StockReservation, status domain.ReservationStatus, at time.Time) {
+	stored.Status = status
+	stored.ResolvedAt = &at
+	r.reservations[stored.ID] = stored
+
+	reservation.Status = status
+	reservation.ResolvedAt = &at
+}
+
+func (r *StockReservationRepository) FindExpired(ctx context.Context, at time.Time, limit int) ([]*domain.StockReservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []*domain.StockReservation
+	for _, stored := range r.reservations {
+		if stored.Status == domain.ReservationPending && stored.IsExpired(at) {
+			reservation := stored
+			expired = append(expired, &reservation)
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool {
+		if !expired[i].ExpiresAt.Equal(expired[j].ExpiresAt) {
+			return expired[i].ExpiresAt.Before(expired[j].ExpiresAt)
+		}
+		return expired[i].ID < expired[j].ID
+	})
+
+	if limit < len(expired) {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+// snapshot and restore let the unit of work roll back a failed transaction.
+func (r *StockReservationRepository) snapshot() map[string]domain.StockReservation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservations := make(map[string]domain.StockReservation, len(r.reservations))
+	for id, reservation := range r.reservations {
+		reservations[id] = reservation
+	}
+	return reservations
+}
+
+func (r *StockReservationRepository) restore(reservations map[string]domain.StockReservation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reservations = reservations
+}
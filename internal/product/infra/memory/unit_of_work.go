@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"go-architecture/internal/product/application"
+	"go-architecture/internal/product/domain"
+)
+
+// UnitOfWork runs functions one at a time against the repositories and
+// restores their previous contents when fn fails. Writes made outside Do
+// while a function is running are lost on rollback.
+type UnitOfWork struct {
+	mu           sync.Mutex
+	repo         *ProductRepository
+	movements    *StockMovementRepository
+	reservations *StockReservationRepository
+}
+
+func NewUnitOfWork(repo *ProductRepository, movements *StockMovementRepository, reservations *StockReservationRepository) *UnitOfWork {
+	return &UnitOfWork{repo: repo, movements: movements, reservations: reservations}
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos application.Repositories) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	products, movements, reservations := u.repo.snapshot(), u.movements.snapshot(), u.reservations.snapshot()
+	if err := fn(ctx, u); err != nil {
+		u.repo.restore(products)
+		u.movements.restore(movements)
+		u.reservations.restore(reservations)
+		return err
+	}
+
+	return nil
+}
+
+func (u *UnitOfWork) Products() domain.ProductRepository {
+	return u.repo
+}
+
+func (u *UnitOfWork) StockMovements() domain.StockMovementRepository {
+	return u.movements
+}
+
+func (u *UnitOfWork) StockReservations() domain.StockReservationRepository {
+	return u.reservations
+}
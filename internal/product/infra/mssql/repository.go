@@ -8,13 +8,15 @@ import (
 	"time"
 
 	"go-architecture/internal/product/domain"
+	productquery "go-architecture/internal/product/infra/query"
+	"go-architecture/internal/shared/database"
 	apperrors "go-architecture/internal/shared/errors"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type ProductRepository struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 func NewProductRepository(db *sqlx.DB) *ProductRepository {
@@ -25,35 +27,67 @@ type productModel struct {
 	ID          string         `db:"id"`
 	Name        string         `db:"name"`
 	Description sql.NullString `db:"description"`
-	Price       float64        `db:"price"`
+	Price       string         `db:"price"`
+	Currency    string         `db:"currency"`
 	Stock       int            `db:"stock"`
 	Category    string         `db:"category"`
 	Active      bool           `db:"active"`
+	Status      string         `db:"status"`
 	CreatedAt   time.Time      `db:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at"`
+	CreatedBy   sql.NullString `db:"created_by"`
+	UpdatedBy   sql.NullString `db:"updated_by"`
+	Version     int64          `db:"version"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
+	DeletedBy   sql.NullString `db:"deleted_by"`
+	Reserved    int            `db:"reserved"`
+}
+
+type searchModel struct {
+	productModel
+	Rank float64 `db:"rank"`
 }
 
 func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
-	query := `INSERT INTO products (id, name, description, price, stock, category, active, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO products (` + productquery.ProductColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	q := r.db.Rebind(query)
 	_, err := r.db.ExecContext(ctx, q,
 		product.ID,
 		product.Name,
 		product.Description,
-		product.Price.Value(),
+		product.Price.String(),
+		product.Price.Currency().Code(),
 		product.Stock,
 		product.Category,
-		product.Active,
+		product.IsActive(),
 		product.CreatedAt,
 		product.UpdatedAt,
+		database.NullString(product.CreatedBy),
+		database.NullString(product.UpdatedBy),
+		product.Version,
+		product.Status,
+		database.NullTime(product.DeletedAt),
+		database.NullString(product.DeletedBy),
+		product.Reserved,
 	)
 	return err
 }
 
 func (r *ProductRepository) FindByID(ctx context.Context, id string) (*domain.Product, error) {
-	query := `SELECT id, name, description, price, stock, category, active, created_at, updated_at FROM products WHERE id = ?`
+	return r.findByID(ctx, id, false)
+}
+
+func (r *ProductRepository) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Product, error) {
+	return r.findByID(ctx, id, true)
+}
+
+func (r *ProductRepository) findByID(ctx context.Context, id string, includeDeleted bool) (*domain.Product, error) {
+	query := `SELECT ` + productquery.ProductColumns + ` FROM products WHERE id = ?`
+	if !includeDeleted {
+		query += ` AND deleted_at IS NULL`
+	}
 	q := r.db.Rebind(query)
 
 	var m productModel
@@ -65,48 +99,11 @@ func (r *ProductRepository) FindByID(ctx context.Context, id string) (*domain.Pr
 		return nil, err
 	}
 
-	desc := ""
-	if m.Description.Valid {
-		desc = m.Description.String
-	}
-
-	price, err := domain.NewPrice(m.Price)
-	if err != nil {
-		return nil, err
-	}
-
-	return &domain.Product{
-		ID:          m.ID,
-		Name:        m.Name,
-		Description: desc,
-		Price:       price,
-		Stock:       m.Stock,
-		Category:    m.Category,
-		Active:      m.Active,
-		CreatedAt:   m.CreatedAt,
-		UpdatedAt:   m.UpdatedAt,
-	}, nil
+	return r.toDomain(&m)
 }
 
 func (r *ProductRepository) FindAll(ctx context.Context, filters domain.ProductFilters) ([]*domain.Product, error) {
-	var sb strings.Builder
-	sb.WriteString("SELECT id, name, description, price, stock, category, active, created_at, updated_at FROM products WHERE 1=1")
-	args := []interface{}{}
-
-	if filters.Category != "" {
-		sb.WriteString(" AND category = ?")
-		args = append(args, filters.Category)
-	}
-	if filters.Active != nil {
-		sb.WriteString(" AND active = ?")
-		args = append(args, *filters.Active)
-	}
-
-	// SQL Server pagination requires ORDER BY when using OFFSET/FETCH
-	sb.WriteString(" ORDER BY created_at DESC OFFSET ? ROWS FETCH NEXT ? ROWS ONLY")
-	args = append(args, filters.Offset, filters.Limit)
-
-	q := r.db.Rebind(sb.String())
+	q, args := productquery.FindProducts(database.MSSQL, filters)
 
 	var models []productModel
 	if err := r.db.SelectContext(ctx, &models, q, args...); err != nil {
@@ -114,43 +111,43 @@ func (r *ProductRepository) FindAll(ctx context.Context, filters domain.ProductF
 	}
 
 	products := make([]*domain.Product, 0, len(models))
-	for _, m := range models {
-		desc := ""
-		if m.Description.Valid {
-			desc = m.Description.String
-		}
-		price, err := domain.NewPrice(m.Price)
+	for i := range models {
+		product, err := r.toDomain(&models[i])
 		if err != nil {
 			return nil, err
 		}
-		products = append(products, &domain.Product{
-			ID:          m.ID,
-			Name:        m.Name,
-			Description: desc,
-			Price:       price,
-			Stock:       m.Stock,
-			Category:    m.Category,
-			Active:      m.Active,
-			CreatedAt:   m.CreatedAt,
-			UpdatedAt:   m.UpdatedAt,
-		})
+		products = append(products, product)
 	}
 
 	return products, nil
 }
 
+func (r *ProductRepository) Count(ctx context.Context, filters domain.ProductFilters) (int, error) {
+	q, args := productquery.CountProducts(database.MSSQL, filters)
+
+	var count int
+	err := r.db.GetContext(ctx, &count, q, args...)
+	return count, err
+}
+
 func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
-	query := `UPDATE products SET name = ?, description = ?, price = ?, stock = ?, category = ?, active = ?, updated_at = ? WHERE id = ?`
+	query := `UPDATE products SET name = ?, description = ?, price = ?, currency = ?, stock = ?, category = ?, active = ?, status = ?, updated_at = ?, updated_by = ?, deleted_at = ?, deleted_by = ?, version = version + 1 WHERE id = ? AND version = ?`
 	q := r.db.Rebind(query)
 	res, err := r.db.ExecContext(ctx, q,
 		product.Name,
 		product.Description,
-		product.Price.Value(),
+		product.Price.String(),
+		product.Price.Currency().Code(),
 		product.Stock,
 		product.Category,
-		product.Active,
+		product.IsActive(),
+		product.Status,
 		product.UpdatedAt,
+		database.NullString(product.UpdatedBy),
+		database.NullTime(product.DeletedAt),
+		database.NullString(product.DeletedBy),
 		product.ID,
+		product.Version,
 	)
 	if err != nil {
 		return err
@@ -160,26 +157,34 @@ func (r *ProductRepository) Update(ctx context.Context, product *domain.Product)
 		return err
 	}
 	if rows == 0 {
-		return apperrors.ErrNotFound
+		return r.updateConflict(ctx, product.ID)
 	}
+	product.Version++
 	return nil
 }
 
-func (r *ProductRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM products WHERE id = ?`
-	q := r.db.Rebind(query)
-	res, err := r.db.ExecContext(ctx, q, id)
-	if err != nil {
+// updateConflict tells apart a missing row from a stale version after a
+// version-checked UPDATE matched no rows.
+func (r *ProductRepository) updateConflict(ctx context.Context, id string) error {
+	q := r.db.Rebind(`SELECT COUNT(1) FROM products WHERE id = ?`)
+	var count int
+	if err := r.db.GetContext(ctx, &count, q, id); err != nil {
 		return err
 	}
-	rows, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows == 0 {
+	if count == 0 {
 		return apperrors.ErrNotFound
 	}
-	return nil
+	return apperrors.ErrConcurrencyConflict
+}
+
+func (r *ProductRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	q := r.db.Rebind(`DELETE FROM products WHERE deleted_at < ?`)
+	res, err := r.db.ExecContext(ctx, q, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := res.RowsAffected()
+	return int(rows), err
 }
 
 func (r *ProductRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
@@ -192,4 +197,147 @@ func (r *ProductRepository) ExistsByName(ctx context.Context, name string) (bool
 	return existsInt == 1, nil
 }
 
-// no helper needed; CreatedAt/UpdatedAt are scanned as time.Time by sqlx
+// Search uses the full-text index created by migration 008 and, when Full-Text
+// Search is not installed, falls back to LIKE on word starts. Either way a
+// match in the name ranks higher.
+func (r *ProductRepository) Search(ctx context.Context, search domain.ProductSearch) ([]*domain.ProductMatch, int, error) {
+	var indexed int
+	if err := r.db.GetContext(ctx, &indexed, `SELECT COUNT(*) FROM sys.fulltext_indexes WHERE object_id = OBJECT_ID(N'dbo.products')`); err != nil {
+		return nil, 0, err
+	}
+
+	sq := likeSearch(search.Terms)
+	if indexed > 0 {
+		sq = fullTextSearch(search.Terms)
+	}
+
+	q := r.db.Rebind(sq.query)
+	var models []searchModel
+	if err := r.db.SelectContext(ctx, &models, q, append(sq.args, search.Offset, search.Limit)...); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, r.db.Rebind(sq.count), sq.countArgs...); err != nil {
+		return nil, 0, err
+	}
+
+	matches := make([]*domain.ProductMatch, len(models))
+	for i := range models {
+		product, err := r.toDomain(&models[i].productModel)
+		if err != nil {
+			return nil, 0, err
+		}
+		matches[i] = &domain.ProductMatch{Product: product, Rank: models[i].Rank}
+	}
+
+	return matches, total, nil
+}
+
+// searchSQL is a search query, to be followed by OFFSET and FETCH arguments,
+// and the query counting its matches.
+type searchSQL struct {
+	query     string
+	args      []interface{}
+	count     string
+	countArgs []interface{}
+}
+
+// fullTextSearch ranks with CONTAINSTABLE over both columns, plus a bonus
+// when the name alone matches. SQL Server requires all terms of a condition
+// to match within one column.
+func fullTextSearch(terms []string) searchSQL {
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = `"` + term + `*"`
+	}
+	condition := strings.Join(prefixes, " AND ")
+
+	return searchSQL{
+		query: `SELECT ` + productquery.ProductColumns + `, rank FROM products
+JOIN (
+	SELECT f.[KEY] AS match_id, f.[RANK] + 2 * COALESCE(n.[RANK], 0) AS rank
+	FROM CONTAINSTABLE(products, ([name], [description]), ?) AS f
+	LEFT JOIN CONTAINSTABLE(products, [name], ?) AS n ON n.[KEY] = f.[KEY]
+) AS m ON products.id = m.match_id
+WHERE deleted_at IS NULL
+ORDER BY rank DESC, created_at DESC, id DESC
+OFFSET ? ROWS FETCH NEXT ? ROWS ONLY`,
+		args:      []interface{}{condition, condition},
+		count:     `SELECT COUNT(*) FROM products JOIN CONTAINSTABLE(products, ([name], [description]), ?) AS f ON products.id = f.[KEY] WHERE deleted_at IS NULL`,
+		countArgs: []interface{}{condition},
+	}
+}
+
+// likeSearch finds words starting with each term by looking for the term
+// after a space, in the name or description prefixed with one. The binary
+// collation keeps accents significant, as in the full-text catalog.
+func likeSearch(terms []string) searchSQL {
+	const (
+		name        = `' ' + LOWER(name) COLLATE Latin1_General_BIN2 LIKE ? ESCAPE '\'`
+		description = `' ' + LOWER(COALESCE(description, '')) COLLATE Latin1_General_BIN2 LIKE ? ESCAPE '\'`
+	)
+
+	var (
+		scores, conditions []string
+		patterns           []interface{}
+	)
+	for _, term := range terms {
+		pattern := "% " + database.EscapeLike(term) + "%"
+		scores = append(scores, "CASE WHEN "+name+" THEN 10 ELSE 0 END", "CASE WHEN "+description+" THEN 1 ELSE 0 END")
+		conditions = append(conditions, "("+name+" OR "+description+")")
+		patterns = append(patterns, pattern, pattern)
+	}
+	where := strings.Join(conditions, " AND ")
+
+	return searchSQL{
+		query: `SELECT ` + productquery.ProductColumns + `, rank FROM (
+	SELECT ` + productquery.ProductColumns + `, ` + strings.Join(scores, " + ") + ` AS rank
+	FROM products
+	WHERE deleted_at IS NULL AND ` + where + `
+) AS m
+ORDER BY rank DESC, created_at DESC, id DESC
+OFFSET ? ROWS FETCH NEXT ? ROWS ONLY`,
+		args:      append(append([]interface{}{}, patterns...), patterns...),
+		count:     `SELECT COUNT(*) FROM products WHERE deleted_at IS NULL AND ` + where,
+		countArgs: patterns,
+	}
+}
+
+// toDomain maps a row to the entity; CreatedAt/UpdatedAt are scanned as
+// time.Time by sqlx.
+func (r *ProductRepository) toDomain(m *productModel) (*domain.Product, error) {
+	desc := ""
+	if m.Description.Valid {
+		desc = m.Description.String
+	}
+
+	price, err := domain.ParsePrice(m.Price, m.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var deletedAt *time.Time
+	if m.DeletedAt.Valid {
+		t := m.DeletedAt.Time
+		deletedAt = &t
+	}
+
+	return &domain.Product{
+		ID:          m.ID,
+		Name:        m.Name,
+		Description: desc,
+		Price:       price,
+		Stock:       m.Stock,
+		Category:    m.Category,
+		Status:      domain.ProductStatus(m.Status),
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+		CreatedBy:   m.CreatedBy.String,
+		UpdatedBy:   m.UpdatedBy.String,
+		Version:     m.Version,
+		DeletedAt:   deletedAt,
+		DeletedBy:   m.DeletedBy.String,
+		Reserved:    m.Reserved,
+	}, nil
+}
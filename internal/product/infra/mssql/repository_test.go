@@ -0,0 +1,58 @@
+package mssql_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	_ "github.com/denisenkom/go-mssqldb"
+
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/product/infra/mssql"
+	"go-architecture/internal/product/infra/repotest"
+	"go-architecture/internal/shared/config"
+	"go-architecture/internal/shared/database"
+	"go-architecture/internal/shared/logger"
+	"go-architecture/migrations"
+)
+
+// The contract runs against a real server only when TEST_MSSQL_DSN is set; the
+// products table, and with it the stock ledger and reservations, is emptied
+// before every subtest.
+func TestProductRepository(t *testing.T) {
+	dsn := os.Getenv("TEST_MSSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MSSQL_DSN not set")
+	}
+
+	db, err := database.Open(config.DatabaseConfig{Driver: database.DriverMSSQL, DSN: dsn}, true, logger.NewLogger())
+	if err != nil {
+		t.Fatalf("open mssql: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := database.Migrate(context.Background(), db, database.DriverMSSQL, migrations.FS); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	repotest.TestProductRepository(t, func(t *testing.T) domain.ProductRepository {
+		if _, err := db.Exec(`DELETE FROM products`); err != nil {
+			t.Fatalf("empty products: %v", err)
+		}
+		return mssql.NewProductRepository(db)
+	})
+
+	repotest.TestStockMovementRepository(t, func(t *testing.T) (domain.ProductRepository, domain.StockMovementRepository) {
+		if _, err := db.Exec(`DELETE FROM products`); err != nil {
+			t.Fatalf("empty products: %v", err)
+		}
+		return mssql.NewProductRepository(db), mssql.NewStockMovementRepository(db)
+	})
+
+	repotest.TestStockReservationRepository(t, func(t *testing.T) (domain.ProductRepository, domain.StockReservationRepository) {
+		if _, err := db.Exec(`DELETE FROM products`); err != nil {
+			t.Fatalf("empty products: %v", err)
+		}
+		return mssql.NewProductRepository(db), mssql.NewStockReservationRepository(db)
+	})
+}
@@ -0,0 +1,94 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-architecture/internal/product/domain"
+	productquery "go-architecture/internal/product/infra/query"
+	"go-architecture/internal/shared/database"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type StockMovementRepository struct {
+	db database.Querier
+}
+
+func NewStockMovementRepository(db *sqlx.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: db}
+}
+
+type stockMovementModel struct {
+	ID         string         `db:"id"`
+	ProductID  string         `db:"product_id"`
+	Quantity   int            `db:"quantity"`
+	Reason     string         `db:"reason"`
+	StockAfter int            `db:"stock_after"`
+	Note       sql.NullString `db:"note"`
+	CreatedAt  time.Time      `db:"created_at"`
+	CreatedBy  sql.NullString `db:"created_by"`
+}
+
+func (r *StockMovementRepository) Create(ctx context.Context, movement *domain.StockMovement) error {
+	query := `INSERT INTO stock_movements (` + productquery.StockMovementColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	q := r.db.Rebind(query)
+	_, err := r.db.ExecContext(ctx, q,
+		movement.ID,
+		movement.ProductID,
+		movement.Quantity,
+		movement.Reason,
+		movement.StockAfter,
+		database.NullString(movement.Note),
+		movement.CreatedAt,
+		database.NullString(movement.CreatedBy),
+	)
+	return err
+}
+
+func (r *StockMovementRepository) FindAll(ctx context.Context, filters domain.StockMovementFilters) ([]*domain.StockMovement, error) {
+	q, args := productquery.FindStockMovements(database.MSSQL, filters)
+
+	var models []stockMovementModel
+	if err := r.db.SelectContext(ctx, &models, q, args...); err != nil {
+		return nil, err
+	}
+
+	movements := make([]*domain.StockMovement, len(models))
+	for i := range models {
+		movements[i] = r.toDomain(&models[i])
+	}
+	return movements, nil
+}
+
+func (r *StockMovementRepository) Count(ctx context.Context, productID string) (int, error) {
+	q := r.db.Rebind(`SELECT COUNT(*) FROM stock_movements WHERE product_id = ?`)
+
+	var count int
+	err := r.db.GetContext(ctx, &count, q, productID)
+	return count, err
+}
+
+func (r *StockMovementRepository) Balance(ctx context.Context, productID string) (int, error) {
+	q := r.db.Rebind(`SELECT COALESCE(SUM(quantity), 0) FROM stock_movements WHERE product_id = ?`)
+
+	var balance int
+	err := r.db.GetContext(ctx, &balance, q, productID)
+	return balance, err
+}
+
+func (r *StockMovementRepository) toDomain(m *stockMovementModel) *domain.StockMovement {
+	return &domain.StockMovement{
+		ID:         m.ID,
+		ProductID:  m.ProductID,
+		Quantity:   m.Quantity,
+		Reason:     domain.MovementReason(m.Reason),
+		StockAfter: m.StockAfter,
+		Note:       m.Note.String,
+		CreatedAt:  m.CreatedAt,
+		CreatedBy:  m.CreatedBy.String,
+	}
+}
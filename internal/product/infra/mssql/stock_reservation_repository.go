@@ -0,0 +1,251 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go-architecture/internal/product/domain"
+	productquery "go-architecture/internal/product/infra/query"
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type StockReservationRepository struct {
+	db database.Querier
+}
+
+func NewStockReservationRepository(db *sqlx.DB) *StockReservationRepository {
+	return &StockReservationRepository{db: db}
+}
+
+type stockReservationModel struct {
+	ID         string         `db:"id"`
+	ProductID  string         `db:"product_id"`
+	Quantity   int            `db:"quantity"`
+	Status     string         `db:"status"`
+	ExpiresAt  time.Time      `db:"expires_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+	CreatedBy  sql.NullString `db:"created_by"`
+	ResolvedAt sql.NullTime   `db:"resolved_at"`
+}
+
+// Reserve claims the units with an UPDATE that only matches while enough
+// stock is available, so concurrent reservations cannot oversell.
+func (r *StockReservationRepository) Reserve(ctx context.Context, reservation *domain.StockReservation) error {
+	q := r.db.Rebind(`UPDATE products
+SET reserved = reserved + ?, version = version + 1
+WHERE id = ? AND deleted_at IS NULL AND status = 'active' AND stock - reserved >= ?`)
+
+	result, err := r.db.ExecContext(ctx, q, reservation.Quantity, reservation.ProductID, reservation.Quantity)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return r.reserveConflict(ctx, reservation.ProductID)
+	}
+
+	q = r.db.Rebind(`INSERT INTO stock_reservations (` + productquery.StockReservationColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+
+	_, err = r.db.ExecContext(ctx, q,
+		reservation.ID,
+		reservation.ProductID,
+		reservation.Quantity,
+		reservation.Status,
+		reservation.ExpiresAt,
+		reservation.CreatedAt,
+		database.NullString(reservation.CreatedBy),
+		database.NullTime(reservation.ResolvedAt),
+	)
+	return err
+}
+
+// reserveConflict tells why a reserving UPDATE matched no product.
+func (r *StockReservationRepository) reserveConflict(ctx context.Context, productID string) error {
+	q := r.db.Rebind(`SELECT status FROM products WHERE id = ? AND deleted_at IS NULL`)
+
+	var status string
+	if err := r.db.GetContext(ctx, &status, q, productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+
+	if domain.ProductStatus(status) != domain.StatusActive {
+		return domain.ErrProductNotOnSale
+	}
+	return domain.ErrInsufficientStock
+}
+
+func (r *StockReservationRepository) FindByID(ctx context.Context, id string) (*domain.StockReservation, error) {
+	q := r.db.Rebind(`SELECT ` + productquery.StockReservationColumns + ` FROM stock_reservations WHERE id = ?`)
+
+	var m stockReservationModel
+	if err := r.db.GetContext(ctx, &m, q, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&m), nil
+}
+
+// Confirm only matches while the product is not deleted, so stock held for a
+// product that was deleted since is never sold.
+func (r *StockReservationRepository) Confirm(ctx context.Context, reservation *domain.StockReservation, at time.Time) (int, error) {
+	q := r.db.Rebind(`UPDATE stock_reservations
+SET status = 'confirmed', resolved_at = ?
+WHERE id = ? AND status = 'pending' AND expires_at > ?
+	AND EXISTS (SELECT 1 FROM products WHERE id = stock_reservations.product_id AND deleted_at IS NULL)`)
+
+	err := r.resolve(ctx, reservation.ID, q, at, reservation.ID, at)
+	if errors.Is(err, domain.ErrReservationExpired) {
+		err = r.confirmConflict(ctx, reservation.ProductID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	q = r.db.Rebind(`UPDATE products
+SET stock = stock - ?, reserved = reserved - ?, version = version + 1
+WHERE id = ? AND deleted_at IS NULL`)
+	result, err := r.db.ExecContext(ctx, q, reservation.Quantity, reservation.Quantity, reservation.ProductID)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if rows == 0 {
+		return 0, domain.ErrProductNotOnSale
+	}
+
+	// OUTPUT cannot return rows from products, which has a trigger; the row
+	// just updated stays locked until the transaction ends.
+	var stock int
+	if err := r.db.GetContext(ctx, &stock, r.db.Rebind(`SELECT stock FROM products WHERE id = ?`), reservation.ProductID); err != nil {
+		return 0, err
+	}
+
+	reservation.Status = domain.ReservationConfirmed
+	reservation.ResolvedAt = &at
+	return stock, nil
+}
+
+// confirmConflict tells why a confirming UPDATE matched a reservation that is
+// still pending: its product was deleted, or it expired.
+func (r *StockReservationRepository) confirmConflict(ctx context.Context, productID string) error {
+	var deleted int
+	err := r.db.GetContext(ctx, &deleted, r.db.Rebind(`SELECT COUNT(*) FROM products WHERE id = ? AND deleted_at IS NOT NULL`), productID)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		return domain.ErrProductNotOnSale
+	}
+
+	return domain.ErrReservationExpired
+}
+
+func (r *StockReservationRepository) Release(ctx context.Context, reservation *domain.StockReservation, status domain.ReservationStatus, at time.Time) error {
+	q := r.db.Rebind(`UPDATE stock_reservations
+SET status = ?, resolved_at = ?
+WHERE id = ? AND status = 'pending'`)
+
+	if err := r.resolve(ctx, reservation.ID, q, status, at, reservation.ID); err != nil {
+		return err
+	}
+
+	q = r.db.Rebind(`UPDATE products SET reserved = reserved - ?, version = version + 1 WHERE id = ?`)
+	if _, err := r.db.ExecContext(ctx, q, reservation.Quantity, reservation.ProductID); err != nil {
+		return err
+	}
+
+	reservation.Status = status
+	reservation.ResolvedAt = &at
+	return nil
+}
+
+// resolve runs an UPDATE that only matches the pending reservation id and
+// tells why it matched nothing: the reservation is gone, already resolved,
+// or, while still pending, expired.
+func (r *StockReservationRepository) resolve(ctx context.Context, id, q string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	var status string
+	if err := r.db.GetContext(ctx, &status, r.db.Rebind(`SELECT status FROM stock_reservations WHERE id = ?`), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+
+	if domain.ReservationStatus(status) == domain.ReservationPending {
+		return domain.ErrReservationExpired
+	}
+	return domain.ErrReservationNotPending
+}
+
+func (r *StockReservationRepository) FindExpired(ctx context.Context, at time.Time, limit int) ([]*domain.StockReservation, error) {
+	q := r.db.Rebind(`SELECT ` + productquery.StockReservationColumns + `
+FROM stock_reservations
+WHERE status = 'pending' AND expires_at <= ?
+ORDER BY expires_at, id
+OFFSET 0 ROWS FETCH NEXT ? ROWS ONLY`)
+
+	var models []stockReservationModel
+	if err := r.db.SelectContext(ctx, &models, q, at, limit); err != nil {
+		return nil, err
+	}
+
+	reservations := make([]*domain.StockReservation, len(models))
+	for i := range models {
+		reservations[i] = r.toDomain(&models[i])
+	}
+	return reservations, nil
+}
+
+func (r *StockReservationRepository) toDomain(m *stockReservationModel) *domain.StockReservation {
+	var resolvedAt *time.Time
+	if m.ResolvedAt.Valid {
+		t := m.ResolvedAt.Time
+		resolvedAt = &t
+	}
+
+	return &domain.StockReservation{
+		ID:         m.ID,
+		ProductID:  m.ProductID,
+		Quantity:   m.Quantity,
+		Status:     domain.ReservationStatus(m.Status),
+		ExpiresAt:  m.ExpiresAt,
+		CreatedAt:  m.CreatedAt,
+		CreatedBy:  m.CreatedBy.String,
+		ResolvedAt: resolvedAt,
+	}
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"go-architecture/internal/product/domain"
+	productquery "go-architecture/internal/product/infra/query"
+	"go-architecture/internal/shared/database"
 	apperrors "go-architecture/internal/shared/errors"
 )
 
 type ProductRepository struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 func NewProductRepository(db *sqlx.DB) *ProductRepository {
@@ -20,21 +23,34 @@ func NewProductRepository(db *sqlx.DB) *ProductRepository {
 }
 
 type productModel struct {
-	ID          string    `db:"id"`
-	Name        string    `db:"name"`
-	Description string    `db:"description"`
-	Price       float64   `db:"price"`
-	Stock       int       `db:"stock"`
-	Category    string    `db:"category"`
-	Active      bool      `db:"active"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID          string         `db:"id"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Price       string         `db:"price"`
+	Currency    string         `db:"currency"`
+	Stock       int            `db:"stock"`
+	Category    string         `db:"category"`
+	Active      bool           `db:"active"`
+	Status      string         `db:"status"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+	CreatedBy   sql.NullString `db:"created_by"`
+	UpdatedBy   sql.NullString `db:"updated_by"`
+	Version     int64          `db:"version"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
+	DeletedBy   sql.NullString `db:"deleted_by"`
+	Reserved    int            `db:"reserved"`
+}
+
+type searchModel struct {
+	productModel
+	Rank float64 `db:"rank"`
 }
 
 func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
 	query := `
-		INSERT INTO products (id, name, description, price, stock, category, active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO products (` + productquery.ProductColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
 
 	_, err := r.db.ExecContext(
@@ -43,23 +59,42 @@ func (r *ProductRepository) Create(ctx context.Context, product *domain.Product)
 		product.ID,
 		product.Name,
 		product.Description,
-		product.Price.Value(),
+		product.Price.String(),
+		product.Price.Currency().Code(),
 		product.Stock,
 		product.Category,
-		product.Active,
+		product.IsActive(),
 		product.CreatedAt,
 		product.UpdatedAt,
+		database.NullString(product.CreatedBy),
+		database.NullString(product.UpdatedBy),
+		product.Version,
+		product.Status,
+		database.NullTime(product.DeletedAt),
+		database.NullString(product.DeletedBy),
+		product.Reserved,
 	)
 
 	return err
 }
 
 func (r *ProductRepository) FindByID(ctx context.Context, id string) (*domain.Product, error) {
+	return r.findByID(ctx, id, false)
+}
+
+func (r *ProductRepository) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Product, error) {
+	return r.findByID(ctx, id, true)
+}
+
+func (r *ProductRepository) findByID(ctx context.Context, id string, includeDeleted bool) (*domain.Product, error) {
 	query := `
-		SELECT id, name, description, price, stock, category, active, created_at, updated_at
+		SELECT ` + productquery.ProductColumns + `
 		FROM products
 		WHERE id = $1
 	`
+	if !includeDeleted {
+		query += `AND deleted_at IS NULL`
+	}
 
 	var model productModel
 	err := r.db.GetContext(ctx, &model, query, id)
@@ -74,28 +109,7 @@ func (r *ProductRepository) FindByID(ctx context.Context, id string) (*domain.Pr
 }
 
 func (r *ProductRepository) FindAll(ctx context.Context, filters domain.ProductFilters) ([]*domain.Product, error) {
-	query := `
-		SELECT id, name, description, price, stock, category, active, created_at, updated_at
-		FROM products
-		WHERE 1=1
-	`
-	args := []interface{}{}
-	argCount := 1
-
-	if filters.Category != "" {
-		query += ` AND category = $` + string(rune(argCount+48))
-		args = append(args, filters.Category)
-		argCount++
-	}
-
-	if filters.Active != nil {
-		query += ` AND active = $` + string(rune(argCount+48))
-		args = append(args, *filters.Active)
-		argCount++
-	}
-
-	query += ` ORDER BY created_at DESC LIMIT $` + string(rune(argCount+48)) + ` OFFSET $` + string(rune(argCount+49))
-	args = append(args, filters.Limit, filters.Offset)
+	query, args := productquery.FindProducts(database.Postgres, filters)
 
 	var models []productModel
 	err := r.db.SelectContext(ctx, &models, query, args...)
@@ -115,11 +129,20 @@ func (r *ProductRepository) FindAll(ctx context.Context, filters domain.ProductF
 	return products, nil
 }
 
+func (r *ProductRepository) Count(ctx context.Context, filters domain.ProductFilters) (int, error) {
+	query, args := productquery.CountProducts(database.Postgres, filters)
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, args...)
+	return count, err
+}
+
 func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
 	query := `
 		UPDATE products
-		SET name = $1, description = $2, price = $3, stock = $4, category = $5, active = $6, updated_at = $7
-		WHERE id = $8
+		SET name = $1, description = $2, price = $3, currency = $4, stock = $5, category = $6, active = $7, status = $8,
+			updated_at = $9, updated_by = $10, deleted_at = $11, deleted_by = $12, version = version + 1
+		WHERE id = $13 AND version = $14
 	`
 
 	result, err := r.db.ExecContext(
@@ -127,12 +150,18 @@ func (r *ProductRepository) Update(ctx context.Context, product *domain.Product)
 		query,
 		product.Name,
 		product.Description,
-		product.Price.Value(),
+		product.Price.String(),
+		product.Price.Currency().Code(),
 		product.Stock,
 		product.Category,
-		product.Active,
+		product.IsActive(),
+		product.Status,
 		product.UpdatedAt,
+		database.NullString(product.UpdatedBy),
+		database.NullTime(product.DeletedAt),
+		database.NullString(product.DeletedBy),
 		product.ID,
+		product.Version,
 	)
 
 	if err != nil {
@@ -145,30 +174,38 @@ func (r *ProductRepository) Update(ctx context.Context, product *domain.Product)
 	}
 
 	if rows == 0 {
-		return apperrors.ErrNotFound
+		return r.updateConflict(ctx, product.ID)
 	}
 
+	product.Version++
 	return nil
 }
 
-func (r *ProductRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM products WHERE id = $1`
-
-	result, err := r.db.ExecContext(ctx, query, id)
-	if err != nil {
+// updateConflict tells apart a missing row from a stale version after a
+// version-checked UPDATE matched no rows.
+func (r *ProductRepository) updateConflict(ctx context.Context, id string) error {
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1)`, id); err != nil {
 		return err
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
+	if !exists {
+		return apperrors.ErrNotFound
 	}
 
-	if rows == 0 {
-		return apperrors.ErrNotFound
+	return apperrors.ErrConcurrencyConflict
+}
+
+func (r *ProductRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	query := `DELETE FROM products WHERE deleted_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	rows, err := result.RowsAffected()
+	return int(rows), err
 }
 
 func (r *ProductRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
@@ -179,12 +216,63 @@ func (r *ProductRepository) ExistsByName(ctx context.Context, name string) (bool
 	return exists, err
 }
 
+// Search matches the search_vector column, where names carry weight A and
+// descriptions weight B, so ts_rank favours name matches.
+func (r *ProductRepository) Search(ctx context.Context, search domain.ProductSearch) ([]*domain.ProductMatch, int, error) {
+	tsquery := prefixQuery(search.Terms)
+
+	query := `
+		SELECT ` + productquery.ProductColumns + `, ts_rank(search_vector, tsq) AS rank
+		FROM products, to_tsquery('simple', $1) AS tsq
+		WHERE search_vector @@ tsq AND deleted_at IS NULL
+		ORDER BY rank DESC, created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	var models []searchModel
+	if err := r.db.SelectContext(ctx, &models, query, tsquery, search.Limit, search.Offset); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM products WHERE search_vector @@ to_tsquery('simple', $1) AND deleted_at IS NULL`
+	if err := r.db.GetContext(ctx, &total, countQuery, tsquery); err != nil {
+		return nil, 0, err
+	}
+
+	matches := make([]*domain.ProductMatch, len(models))
+	for i, model := range models {
+		product, err := r.toDomain(&model.productModel)
+		if err != nil {
+			return nil, 0, err
+		}
+		matches[i] = &domain.ProductMatch{Product: product, Rank: model.Rank}
+	}
+
+	return matches, total, nil
+}
+
+// prefixQuery requires every term as a word prefix, e.g. "desk:* & lamp:*".
+func prefixQuery(terms []string) string {
+	lexemes := make([]string, len(terms))
+	for i, term := range terms {
+		lexemes[i] = term + ":*"
+	}
+	return strings.Join(lexemes, " & ")
+}
+
 func (r *ProductRepository) toDomain(model *productModel) (*domain.Product, error) {
-	price, err := domain.NewPrice(model.Price)
+	price, err := domain.ParsePrice(model.Price, model.Currency)
 	if err != nil {
 		return nil, err
 	}
 
+	var deletedAt *time.Time
+	if model.DeletedAt.Valid {
+		t := model.DeletedAt.Time
+		deletedAt = &t
+	}
+
 	return &domain.Product{
 		ID:          model.ID,
 		Name:        model.Name,
@@ -192,8 +280,14 @@ func (r *ProductRepository) toDomain(model *productModel) (*domain.Product, erro
 		Price:       price,
 		Stock:       model.Stock,
 		Category:    model.Category,
-		Active:      model.Active,
+		Status:      domain.ProductStatus(model.Status),
 		CreatedAt:   model.CreatedAt,
 		UpdatedAt:   model.UpdatedAt,
+		CreatedBy:   model.CreatedBy.String,
+		UpdatedBy:   model.UpdatedBy.String,
+		Version:     model.Version,
+		DeletedAt:   deletedAt,
+		DeletedBy:   model.DeletedBy.String,
+		Reserved:    model.Reserved,
 	}, nil
 }
@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/product/domain"
+	productquery "go-architecture/internal/product/infra/query"
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+type StockReservationRepository struct {
+	db database.Querier
+}
+
+func NewStockReservationRepository(db *sqlx.DB) *StockReservationRepository {
+	return &StockReservationRepository{db: db}
+}
+
+type stockReservationModel struct {
+	ID         string         `db:"id"`
+	ProductID  string         `db:"product_id"`
+	Quantity   int            `db:"quantity"`
+	Status     string         `db:"status"`
+	ExpiresAt  time.Time      `db:"expires_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+	CreatedBy  sql.NullString `db:"created_by"`
+	ResolvedAt sql.NullTime   `db:"resolved_at"`
+}
+
+// Reserve claims the units with an UPDATE that only matches while enough
+// stock is available, so concurrent reservations cannot oversell.
+func (r *StockReservationRepository) Reserve(ctx context.Context, reservation *domain.StockReservation) error {
+	query := `
+		UPDATE products
+		SET reserved = reserved + $1, version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL AND status = 'active' AND stock - reserved >= $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, reservation.Quantity, reservation.ProductID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return r.reserveConflict(ctx, reservation.ProductID)
+	}
+
+	insert := `
+		INSERT INTO stock_reservations (` + productquery.StockReservationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		insert,
+		reservation.ID,
+		reservation.ProductID,
+		reservation.Quantity,
+		reservation.Status,
+		reservation.ExpiresAt,
+		reservation.CreatedAt,
+		database.NullString(reservation.CreatedBy),
+		database.NullTime(reservation.ResolvedAt),
+	)
+
+	return err
+}
+
+// reserveConflict tells why a reserving UPDATE matched no product.
+func (r *StockReservationRepository) reserveConflict(ctx context.Context, productID string) error {
+	var status string
+	err := r.db.GetContext(ctx, &status, `SELECT status FROM products WHERE id = $1 AND deleted_at IS NULL`, productID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+
+	if domain.ProductStatus(status) != domain.StatusActive {
+		return domain.ErrProductNotOnSale
+	}
+
+	return domain.ErrInsufficientStock
+}
+
+func (r *StockReservationRepository) FindByID(ctx context.Context, id string) (*domain.StockReservation, error) {
+	query := `SELECT ` + productquery.StockReservationColumns + ` FROM stock_reservations WHERE id = $1`
+
+	var model stockReservationModel
+	err := r.db.GetContext(ctx, &model, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+// Confirm only matches while the product is not deleted, so stock held for a
+// product that was deleted since is never sold.
+func (r *StockReservationRepository) Confirm(ctx context.Context, reservation *domain.StockReservation, at time.Time) (int, error) {
+	query := `
+		UPDATE stock_reservations
+		SET status = 'confirmed', resolved_at = $1
+		WHERE id = $2 AND status = 'pending' AND expires_at > $1
+			AND EXISTS (SELECT 1 FROM products WHERE id = stock_reservations.product_id AND deleted_at IS NULL)
+	`
+
+	err := r.resolve(ctx, reservation.ID, query, at, reservation.ID)
+	if errors.Is(err, domain.ErrReservationExpired) {
+		err = r.confirmConflict(ctx, reservation.ProductID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var stock int
+	err = r.db.GetContext(
+		ctx,
+		&stock,
+		`UPDATE products SET stock = stock - $1, reserved = reserved - $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL RETURNING stock`,
+		reservation.Quantity,
+		reservation.ProductID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrProductNotOnSale
+		}
+		return 0, err
+	}
+
+	reservation.Status = domain.ReservationConfirmed
+	reservation.ResolvedAt = &at
+	return stock, nil
+}
+
+// confirmConflict tells why a confirming UPDATE matched a reservation that is
+// still pending: its product was deleted, or it expired.
+func (r *StockReservationRepository) confirmConflict(ctx context.Context, productID string) error {
+	var deleted int
+	err := r.db.GetContext(ctx, &deleted, `SELECT COUNT(*) FROM products WHERE id = $1 AND deleted_at IS NOT NULL`, productID)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		return domain.ErrProductNotOnSale
+	}
+
+	return domain.ErrReservationExpired
+}
+
+func (r *StockReservationRepository) Release(ctx context.Context, reservation *domain.StockReservation, status domain.ReservationStatus, at time.Time) error {
+	query := `
+		UPDATE stock_reservations
+		SET status = $1, resolved_at = $2
+		WHERE id = $3 AND status = 'pending'
+	`
+
+	if err := r.resolve(ctx, reservation.ID, query, status, at, reservation.ID); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE products SET reserved = reserved - $1, version = version + 1 WHERE id = $2`,
+		reservation.Quantity,
+		reservation.ProductID,
+	)
+	if err != nil {
+		return err
+	}
+
+	reservation.Status = status
+	reservation.ResolvedAt = &at
+	return nil
+}
+
+// resolve runs an UPDATE that only matches the pending reservation id and
+// tells why it matched nothing: the reservation is gone, already resolved,
+// or, while still pending, expired.
+func (r *StockReservationRepository) resolve(ctx context.Context, id, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows > 0 {
+		return nil
+	}
+
+	var status string
+	if err := r.db.GetContext(ctx, &status, `SELECT status FROM stock_reservations WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+
+	if domain.ReservationStatus(status) == domain.ReservationPending {
+		return domain.ErrReservationExpired
+	}
+
+	return domain.ErrReservationNotPending
+}
+
+func (r *StockReservationRepository) FindExpired(ctx context.Context, at time.Time, limit int) ([]*domain.StockReservation, error) {
+	query := `
+		SELECT ` + productquery.StockReservationColumns + `
+		FROM stock_reservations
+		WHERE status = 'pending' AND expires_at <= $1
+		ORDER BY expires_at, id
+		LIMIT $2
+	`
+
+	var models []stockReservationModel
+	if err := r.db.SelectContext(ctx, &models, query, at, limit); err != nil {
+		return nil, err
+	}
+
+	reservations := make([]*domain.StockReservation, len(models))
+	for i := range models {
+		reservations[i] = r.toDomain(&models[i])
+	}
+
+	return reservations, nil
+}
+
+func (r *StockReservationRepository) toDomain(model *stockReservationModel) *domain.StockReservation {
+	var resolvedAt *time.Time
+	if model.ResolvedAt.Valid {
+		t := model.ResolvedAt.Time
+		resolvedAt = &t
+	}
+
+	return &domain.StockReservation{
+		ID:         model.ID,
+		ProductID:  model.ProductID,
+		Quantity:   model.Quantity,
+		Status:     domain.ReservationStatus(model.Status),
+		ExpiresAt:  model.ExpiresAt,
+		CreatedAt:  model.CreatedAt,
+		CreatedBy:  model.CreatedBy.String,
+		ResolvedAt: resolvedAt,
+	}
+}
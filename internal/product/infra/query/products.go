@@ -0,0 +1,176 @@
+// Package query builds the product SQL shared by every database backend, so
+// that filters are written once and rendered for each dialect.
+package query
+
+import (
+	"strings"
+
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/shared/database"
+)
+
+// ProductColumns are the columns scanned into a product row model.
+const ProductColumns = "id, name, description, price, currency, stock, category, active, created_at, updated_at, created_by, updated_by, version, status, deleted_at, deleted_by, reserved"
+
+// FindProducts selects the products matching filters in their sort order.
+// Products equal on every sort term are ordered by ID so that pages never
+// overlap.
+func FindProducts(d database.Dialect, filters domain.ProductFilters) (string, []interface{}) {
+	q := d.Select(ProductColumns, "products")
+	where(q, filters)
+
+	terms := sortTerms(d, filters)
+	if after := filters.After; after != nil {
+		keyset(q, terms, *after)
+	}
+
+	orderBy := make([]string, len(terms))
+	for i, term := range terms {
+		orderBy[i] = term.expr + " " + term.direction()
+	}
+
+	return q.OrderBy(orderBy...).Paginate(filters.Limit, filters.Offset).Build()
+}
+
+// CountProducts counts every product matching filters, regardless of the
+// page they select.
+func CountProducts(d database.Dialect, filters domain.ProductFilters) (string, []interface{}) {
+	q := d.Select("COUNT(*)", "products")
+	where(q, filters)
+	return q.Build()
+}
+
+// where applies the filters shared by listing and counting.
+func where(q *database.SelectBuilder, filters domain.ProductFilters) {
+	if !filters.IncludeDeleted {
+		q.Where("deleted_at IS NULL")
+	}
+	if len(filters.Categories) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filters.Categories)), ", ")
+		args := make([]interface{}, len(filters.Categories))
+		for i, category := range filters.Categories {
+			args[i] = category
+		}
+		q.Where("category IN ("+placeholders+")", args...)
+	}
+	if filters.Currency != "" {
+		q.Where("currency = ?", filters.Currency)
+	}
+	if filters.Active != nil {
+		q.Where("active = ?", *filters.Active)
+	}
+	if len(filters.Statuses) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filters.Statuses)), ", ")
+		args := make([]interface{}, len(filters.Statuses))
+		for i, status := range filters.Statuses {
+			args[i] = string(status)
+		}
+		q.Where("status IN ("+placeholders+")", args...)
+	}
+	if filters.MinPrice != "" {
+		q.Where("price >= ?", filters.MinPrice)
+	}
+	if filters.MaxPrice != "" {
+		q.Where("price <= ?", filters.MaxPrice)
+	}
+	if filters.MinStock != nil {
+		q.Where("stock >= ?", *filters.MinStock)
+	}
+	if filters.MaxStock != nil {
+		q.Where("stock <= ?", *filters.MaxStock)
+	}
+	if filters.CreatedFrom != nil {
+		q.Where("created_at >= ?", *filters.CreatedFrom)
+	}
+	if filters.CreatedTo != nil {
+		q.Where("created_at <= ?", *filters.CreatedTo)
+	}
+	if filters.UpdatedFrom != nil {
+		q.Where("updated_at >= ?", *filters.UpdatedFrom)
+	}
+	if filters.UpdatedTo != nil {
+		q.Where("updated_at <= ?", *filters.UpdatedTo)
+	}
+	if filters.NamePrefix != "" {
+		q.Where(`LOWER(name) LIKE ? ESCAPE '\'`, database.EscapeLike(strings.ToLower(filters.NamePrefix))+"%")
+	}
+	if filters.NameContains != "" {
+		q.Where(`LOWER(name) LIKE ? ESCAPE '\'`, "%"+database.EscapeLike(strings.ToLower(filters.NameContains))+"%")
+	}
+}
+
+// sortTerm is one ORDER BY expression and the cursor value it is compared
+// with.
+type sortTerm struct {
+	expr  string
+	desc  bool
+	value func(domain.ProductCursor) interface{}
+}
+
+func (t sortTerm) direction() string {
+	if t.desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// sortTerms returns the ordering of filters followed by the ID tiebreaker.
+func sortTerms(d database.Dialect, filters domain.ProductFilters) []sortTerm {
+	order := filters.SortOrder()
+	terms := make([]sortTerm, 0, len(order)+1)
+	for _, s := range order {
+		term := sortTerm{desc: s.Desc}
+		switch s.Field {
+		case domain.SortByName:
+			term.expr = d.Binary("LOWER(name)")
+			term.value = func(c domain.ProductCursor) interface{} { return strings.ToLower(c.Name) }
+		case domain.SortByPrice:
+			term.expr = "price"
+			term.value = func(c domain.ProductCursor) interface{} { return c.Price }
+		case domain.SortByStock:
+			term.expr = "stock"
+			term.value = func(c domain.ProductCursor) interface{} { return c.Stock }
+		case domain.SortByUpdatedAt:
+			term.expr = "updated_at"
+			term.value = func(c domain.ProductCursor) interface{} { return c.UpdatedAt }
+		default:
+			term.expr = "created_at"
+			term.value = func(c domain.ProductCursor) interface{} { return c.CreatedAt }
+		}
+		terms = append(terms, term)
+	}
+
+	return append(terms, sortTerm{
+		expr:  "id",
+		desc:  order[len(order)-1].Desc,
+		value: func(c domain.ProductCursor) interface{} { return c.ID },
+	})
+}
+
+// keyset restricts the query to the rows after cursor in the order of terms.
+// The row-value comparison is spelled out as
+//
+//	(a > ?) OR (a = ? AND b > ?) OR ...
+//
+// because SQL Server has none.
+func keyset(q *database.SelectBuilder, terms []sortTerm, cursor domain.ProductCursor) {
+	var (
+		branches []string
+		args     []interface{}
+	)
+	for i, term := range terms {
+		var conditions []string
+		for _, prev := range terms[:i] {
+			conditions = append(conditions, prev.expr+" = ?")
+			args = append(args, prev.value(cursor))
+		}
+		op := " > ?"
+		if term.desc {
+			op = " < ?"
+		}
+		conditions = append(conditions, term.expr+op)
+		args = append(args, term.value(cursor))
+		branches = append(branches, "("+strings.Join(conditions, " AND ")+")")
+	}
+	q.Where("("+strings.Join(branches, " OR ")+")", args...)
+}
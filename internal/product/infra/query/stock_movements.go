@@ -0,0 +1,19 @@
+package query
+
+import (
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/shared/database"
+)
+
+// StockMovementColumns are the columns scanned into a stock movement row
+// model.
+const StockMovementColumns = "id, product_id, quantity, reason, stock_after, note, created_at, created_by"
+
+// FindStockMovements selects a page of one product's movements, newest
+// first.
+func FindStockMovements(d database.Dialect, filters domain.StockMovementFilters) (string, []interface{}) {
+	q := d.Select(StockMovementColumns, "stock_movements")
+	q.Where("product_id = ?", filters.ProductID)
+
+	return q.OrderBy("created_at DESC", "id DESC").Paginate(filters.Limit, filters.Offset).Build()
+}
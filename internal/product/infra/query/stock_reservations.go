@@ -0,0 +1,5 @@
+package query
+
+// StockReservationColumns are the columns scanned into a stock reservation
+// row model.
+const StockReservationColumns = "id, product_id, quantity, status, expires_at, created_at, created_by, resolved_at"
@@ -0,0 +1,776 @@
+// Package repotest is the conformance suite every domain.ProductRepository
+// implementation must pass, so that the in-memory fake and the SQL backends
+// keep the same semantics.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"go-architecture/internal/product/domain"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+// Factory returns an empty repository. It is called once per subtest.
+type Factory func(t *testing.T) domain.ProductRepository
+
+// baseTime is a whole second in UTC so that it survives every backend's
+// timestamp type unchanged.
+var baseTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// TestProductRepository runs the contract against the repositories returned
+// by newRepo. Listing queries are only checked with a positive limit, which
+// the application always sets, and keyset pages start from cursors of
+// products read back from the repository.
+func TestProductRepository(t *testing.T, newRepo Factory) {
+	t.Run("FindByIDNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindByID(context.Background(), "00000000-0000-0000-0000-000000000000")
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			t.Fatalf("FindByID of a missing product: got %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("CreateAndFindByID", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		want := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		want.Description = "Mechanical, 87 keys"
+		want.UpdatedBy = "updater"
+		create(t, repo, want)
+
+		got, err := repo.FindByID(ctx, want.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		assertEqual(t, got, want)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Monitor", "displays", domain.StatusActive, baseTime)
+		create(t, repo, product)
+
+		price := mustPrice(t, "249.90")
+		if err := product.Update("Monitor 27", "IPS", price, 3, "screens", "editor"); err != nil {
+			t.Fatalf("Update entity: %v", err)
+		}
+		if err := product.Deactivate("editor"); err != nil {
+			t.Fatalf("Deactivate: %v", err)
+		}
+		product.UpdatedAt = baseTime.Add(time.Hour)
+
+		if err := repo.Update(ctx, product); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if product.Version != 2 {
+			t.Fatalf("Version after Update = %d, want 2", product.Version)
+		}
+
+		got, err := repo.FindByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		assertEqual(t, got, product)
+	})
+
+	t.Run("UpdateStaleVersion", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Mouse", "peripherals", domain.StatusActive, baseTime)
+		create(t, repo, product)
+
+		stale := *product
+		if err := repo.Update(ctx, product); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		stale.Name = "Mouse v2"
+		if err := repo.Update(ctx, &stale); !errors.Is(err, apperrors.ErrConcurrencyConflict) {
+			t.Fatalf("Update with a stale version: got %v, want ErrConcurrencyConflict", err)
+		}
+		if stale.Version != 1 {
+			t.Fatalf("Version after a failed Update = %d, want 1", stale.Version)
+		}
+	})
+
+	t.Run("UpdateNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		product := newProduct(t, "Webcam", "peripherals", domain.StatusActive, baseTime)
+		if err := repo.Update(context.Background(), product); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Fatalf("Update of a missing product: got %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("SoftDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Headset", "audio", domain.StatusActive, baseTime)
+		create(t, repo, product)
+		softDelete(t, repo, product, baseTime.Add(time.Hour))
+		create(t, repo, newProduct(t, "Earbuds", "audio", domain.StatusActive, baseTime.Add(-time.Minute)))
+
+		if _, err := repo.FindByID(ctx, product.ID); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Fatalf("FindByID after Delete: got %v, want ErrNotFound", err)
+		}
+		got, err := repo.FindByIDIncludingDeleted(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("FindByIDIncludingDeleted: %v", err)
+		}
+		assertEqual(t, got, product)
+
+		assertNames(t, findAll(t, repo, domain.ProductFilters{Limit: 10}), []string{"Earbuds"})
+		listed := findAll(t, repo, domain.ProductFilters{IncludeDeleted: true, Limit: 10})
+		assertNames(t, listed, []string{"Headset", "Earbuds"})
+		assertEqual(t, listed[0], product)
+		for includeDeleted, want := range map[bool]int{false: 1, true: 2} {
+			count, err := repo.Count(ctx, domain.ProductFilters{IncludeDeleted: includeDeleted})
+			if err != nil {
+				t.Fatalf("Count: %v", err)
+			}
+			if count != want {
+				t.Errorf("Count(IncludeDeleted: %t) = %d, want %d", includeDeleted, count, want)
+			}
+		}
+		if exists, err := repo.ExistsByName(ctx, "Headset"); err != nil || !exists {
+			t.Errorf("ExistsByName of a deleted product = %t, %v; want true", exists, err)
+		}
+
+		if err := got.Restore("restorer"); err != nil {
+			t.Fatalf("Restore: %v", err)
+		}
+		got.UpdatedAt = baseTime.Add(2 * time.Hour)
+		if err := repo.Update(ctx, got); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		restored, err := repo.FindByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("FindByID after Restore: %v", err)
+		}
+		assertEqual(t, restored, got)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		live := newProduct(t, "Amplifier", "audio", domain.StatusActive, baseTime)
+		old := newProduct(t, "Turntable", "audio", domain.StatusActive, baseTime)
+		recent := newProduct(t, "Cassette Deck", "audio", domain.StatusActive, baseTime)
+		for _, product := range []*domain.Product{live, old, recent} {
+			create(t, repo, product)
+		}
+		softDelete(t, repo, old, baseTime.Add(time.Hour))
+		softDelete(t, repo, recent, baseTime.Add(3*time.Hour))
+
+		for _, want := range []int{1, 0} {
+			purged, err := repo.Purge(ctx, baseTime.Add(2*time.Hour))
+			if err != nil {
+				t.Fatalf("Purge: %v", err)
+			}
+			if purged != want {
+				t.Errorf("Purge = %d, want %d", purged, want)
+			}
+		}
+
+		if _, err := repo.FindByIDIncludingDeleted(ctx, old.ID); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("FindByIDIncludingDeleted of a purged product: got %v, want ErrNotFound", err)
+		}
+		assertNames(t, findAll(t, repo, domain.ProductFilters{IncludeDeleted: true, Sort: []domain.ProductSort{{Field: domain.SortByName}}, Limit: 10}),
+			[]string{"Amplifier", "Cassette Deck"})
+	})
+
+	t.Run("ExistsByName", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		create(t, repo, newProduct(t, "Speaker", "audio", domain.StatusActive, baseTime))
+
+		for name, want := range map[string]bool{"Speaker": true, "Speakers": false, "Speak": false} {
+			got, err := repo.ExistsByName(ctx, name)
+			if err != nil {
+				t.Fatalf("ExistsByName(%q): %v", name, err)
+			}
+			if got != want {
+				t.Errorf("ExistsByName(%q) = %t, want %t", name, got, want)
+			}
+		}
+	})
+
+	t.Run("FindAllFilters", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+
+		active, inactive := true, false
+		tests := []struct {
+			name    string
+			filters domain.ProductFilters
+			want    []string
+		}{
+			{"none", domain.ProductFilters{}, []string{"Product 5", "Product 4", "Product 3", "Product 2", "Product 1"}},
+			{"category", domain.ProductFilters{Categories: []string{"books"}}, []string{"Product 5", "Product 3", "Product 1"}},
+			{"active", domain.ProductFilters{Active: &active}, []string{"Product 5", "Product 3", "Product 2"}},
+			{"inactive", domain.ProductFilters{Active: &inactive}, []string{"Product 4", "Product 1"}},
+			{"category and active", domain.ProductFilters{Categories: []string{"books"}, Active: &inactive}, []string{"Product 1"}},
+			{"status", domain.ProductFilters{Statuses: []domain.ProductStatus{domain.StatusDraft}}, []string{"Product 1"}},
+			{"statuses", domain.ProductFilters{Statuses: []domain.ProductStatus{domain.StatusDraft, domain.StatusDiscontinued}}, []string{"Product 4", "Product 1"}},
+			{"status and category", domain.ProductFilters{Statuses: []domain.ProductStatus{domain.StatusActive}, Categories: []string{"games"}}, []string{"Product 2"}},
+			{"unknown category", domain.ProductFilters{Categories: []string{"garden"}}, nil},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.filters.Limit = 100
+				assertNames(t, findAll(t, repo, tt.filters), tt.want)
+			})
+		}
+	})
+
+	t.Run("FindAllPagination", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+
+		tests := []struct {
+			limit, offset int
+			want          []string
+		}{
+			{2, 0, []string{"Product 5", "Product 4"}},
+			{2, 2, []string{"Product 3", "Product 2"}},
+			{2, 4, []string{"Product 1"}},
+			{2, 5, nil},
+			{10, 1, []string{"Product 4", "Product 3", "Product 2", "Product 1"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("limit=%d,offset=%d", tt.limit, tt.offset), func(t *testing.T) {
+				got := findAll(t, repo, domain.ProductFilters{Limit: tt.limit, Offset: tt.offset})
+				assertNames(t, got, tt.want)
+			})
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+
+		active, inactive := true, false
+		after := domain.ProductCursor{CreatedAt: baseTime.Add(3 * time.Minute), ID: "~"}
+		tests := []struct {
+			name    string
+			filters domain.ProductFilters
+			want    int
+		}{
+			{"none", domain.ProductFilters{}, 5},
+			{"category", domain.ProductFilters{Categories: []string{"books"}}, 3},
+			{"active", domain.ProductFilters{Active: &active}, 3},
+			{"category and active", domain.ProductFilters{Categories: []string{"books"}, Active: &inactive}, 1},
+			{"statuses", domain.ProductFilters{Statuses: []domain.ProductStatus{domain.StatusActive, domain.StatusArchived}}, 3},
+			{"unknown category", domain.ProductFilters{Categories: []string{"garden"}}, 0},
+			{"ignores paging", domain.ProductFilters{Limit: 1, Offset: 2, After: &after}, 5},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := repo.Count(context.Background(), tt.filters)
+				if err != nil {
+					t.Fatalf("Count(%+v): %v", tt.filters, err)
+				}
+				if got != tt.want {
+					t.Errorf("Count(%+v) = %d, want %d", tt.filters, got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("FindAllKeyset", func(t *testing.T) {
+		repo := newRepo(t)
+
+		// Three products share a creation time, so pages must be split on ID.
+		var products []*domain.Product
+		for i, offset := range []time.Duration{0, time.Minute, time.Minute, time.Minute, 2 * time.Minute} {
+			product := newProduct(t, fmt.Sprintf("Product %d", i+1), "books", domain.StatusActive, baseTime.Add(offset))
+			create(t, repo, product)
+			products = append(products, product)
+		}
+
+		sort.Slice(products, func(i, j int) bool {
+			if !products[i].CreatedAt.Equal(products[j].CreatedAt) {
+				return products[i].CreatedAt.After(products[j].CreatedAt)
+			}
+			return products[i].ID > products[j].ID
+		})
+		want := make([]string, len(products))
+		for i, product := range products {
+			want[i] = product.Name
+		}
+
+		var got []*domain.Product
+		filters := domain.ProductFilters{Categories: []string{"books"}, Limit: 2}
+		for pages := 0; ; pages++ {
+			if pages > len(products) {
+				t.Fatalf("keyset pagination does not terminate, got %d products so far", len(got))
+			}
+			page := findAll(t, repo, filters)
+			got = append(got, page...)
+			if len(page) < filters.Limit {
+				break
+			}
+			after := domain.CursorOf(page[len(page)-1])
+			filters.After = &after
+		}
+
+		assertNames(t, got, want)
+	})
+
+	t.Run("FindAllRangeFilters", func(t *testing.T) {
+		repo := newRepo(t)
+		seedCatalog(t, repo)
+
+		one, three, seven, zero := 1, 3, 7, 0
+		at := func(minutes int) *time.Time {
+			t := baseTime.Add(time.Duration(minutes) * time.Minute)
+			return &t
+		}
+		tests := []struct {
+			name    string
+			filters domain.ProductFilters
+			want    []string
+		}{
+			{"categories", domain.ProductFilters{Categories: []string{"books", "toys"}}, []string{"Cherry_Tart", "Banana 100%", "Apple Pie"}},
+			{"currency", domain.ProductFilters{Currency: "EUR"}, []string{"Date Box"}},
+			{"min price", domain.ProductFilters{MinPrice: "5"}, []string{"Date Box", "Cherry_Tart", "Banana 100%", "Apple Pie"}},
+			{"max price", domain.ProductFilters{MaxPrice: "5.00"}, []string{"Cherry_Tart", "apple juice", "Apple Pie"}},
+			{"price range", domain.ProductFilters{MinPrice: "5", MaxPrice: "12"}, []string{"Cherry_Tart", "Banana 100%", "Apple Pie"}},
+			{"min stock", domain.ProductFilters{MinStock: &one}, []string{"Cherry_Tart", "Banana 100%", "apple juice"}},
+			{"max stock", domain.ProductFilters{MaxStock: &zero}, []string{"Date Box", "Apple Pie"}},
+			{"stock range", domain.ProductFilters{MinStock: &three, MaxStock: &seven}, []string{"Cherry_Tart", "Banana 100%"}},
+			{"created range", domain.ProductFilters{CreatedFrom: at(2), CreatedTo: at(4)}, []string{"Cherry_Tart", "Banana 100%", "apple juice"}},
+			{"updated from", domain.ProductFilters{UpdatedFrom: at(5)}, []string{"Date Box", "Banana 100%", "Apple Pie"}},
+			{"updated to", domain.ProductFilters{UpdatedTo: at(4)}, []string{"Cherry_Tart", "apple juice"}},
+			{"name prefix", domain.ProductFilters{NamePrefix: "APPLE"}, []string{"apple juice", "Apple Pie"}},
+			{"name contains", domain.ProductFilters{NameContains: "an"}, []string{"Banana 100%"}},
+			{"literal percent", domain.ProductFilters{NameContains: "0%"}, []string{"Banana 100%"}},
+			{"literal underscore", domain.ProductFilters{NameContains: "y_t"}, []string{"Cherry_Tart"}},
+			{"wildcard prefix", domain.ProductFilters{NamePrefix: "a%"}, nil},
+			{"combined", domain.ProductFilters{Categories: []string{"books"}, MinStock: &one, NameContains: "a"}, []string{"Banana 100%"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.filters.Limit = 100
+				assertNames(t, findAll(t, repo, tt.filters), tt.want)
+
+				count, err := repo.Count(context.Background(), tt.filters)
+				if err != nil {
+					t.Fatalf("Count(%+v): %v", tt.filters, err)
+				}
+				if count != len(tt.want) {
+					t.Errorf("Count(%+v) = %d, want %d", tt.filters, count, len(tt.want))
+				}
+			})
+		}
+	})
+
+	t.Run("FindAllSort", func(t *testing.T) {
+		repo := newRepo(t)
+		seedCatalog(t, repo)
+
+		for _, tt := range sortCases {
+			t.Run(tt.name, func(t *testing.T) {
+				assertNames(t, findAll(t, repo, domain.ProductFilters{Sort: tt.sort, Limit: 100}), tt.want)
+			})
+		}
+	})
+
+	t.Run("FindAllKeysetSorted", func(t *testing.T) {
+		repo := newRepo(t)
+		seedCatalog(t, repo)
+
+		for _, tt := range sortCases {
+			t.Run(tt.name, func(t *testing.T) {
+				var got []*domain.Product
+				filters := domain.ProductFilters{Sort: tt.sort, Limit: 2}
+				for pages := 0; ; pages++ {
+					if pages > len(tt.want) {
+						t.Fatalf("keyset pagination does not terminate, got %d products so far", len(got))
+					}
+					page := findAll(t, repo, filters)
+					got = append(got, page...)
+					if len(page) < filters.Limit {
+						break
+					}
+					after := domain.CursorOf(page[len(page)-1])
+					filters.After = &after
+				}
+				assertNames(t, got, tt.want)
+			})
+		}
+	})
+
+	t.Run("FindAllNonASCIINames", func(t *testing.T) {
+		repo := newRepo(t)
+
+		// "éclair" and "ÉCLAIR" fold to the same name, so only the ID orders
+		// them; a backend folding ASCII letters alone would split them.
+		for i, name := range []string{"éclair", "Éclair glacé", "ÉCLAIR", "Zürich", "Ärger"} {
+			product := newProduct(t, name, "books", domain.StatusActive, baseTime.Add(time.Duration(i)*time.Minute))
+			product.ID = fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1)
+			create(t, repo, product)
+		}
+
+		for _, tt := range []struct {
+			name    string
+			filters domain.ProductFilters
+			want    []string
+		}{
+			{"name prefix", domain.ProductFilters{NamePrefix: "ÉCL"}, []string{"ÉCLAIR", "Éclair glacé", "éclair"}},
+			{"name contains", domain.ProductFilters{NameContains: "ÜR"}, []string{"Zürich"}},
+		} {
+			tt.filters.Limit = 100
+			assertNames(t, findAll(t, repo, tt.filters), tt.want)
+		}
+
+		// Sorted by code point once folded, ties broken by ID.
+		want := []string{"Zürich", "Ärger", "éclair", "ÉCLAIR", "Éclair glacé"}
+		sortByName := []domain.ProductSort{{Field: domain.SortByName}}
+		assertNames(t, findAll(t, repo, domain.ProductFilters{Sort: sortByName, Limit: 100}), want)
+
+		var got []*domain.Product
+		filters := domain.ProductFilters{Sort: sortByName, Limit: 2}
+		for pages := 0; ; pages++ {
+			if pages > len(want) {
+				t.Fatalf("keyset pagination does not terminate, got %d products so far", len(got))
+			}
+			page := findAll(t, repo, filters)
+			got = append(got, page...)
+			if len(page) < filters.Limit {
+				break
+			}
+			after := domain.CursorOf(page[len(page)-1])
+			filters.After = &after
+		}
+		assertNames(t, got, want)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		repo := newRepo(t)
+		seedSearch(t, repo)
+
+		tests := []struct {
+			name          string
+			query         string
+			limit, offset int
+			want          []string
+			total         int
+		}{
+			{"name before description", "keyboard", 10, 0, []string{"Mechanical Keyboard", "Desk Mat"}, 2},
+			{"prefix and case", "KEYB", 10, 0, []string{"Mechanical Keyboard", "Desk Mat"}, 2},
+			{"every term", "mouse keyboard", 10, 0, []string{"Desk Mat"}, 1},
+			{"other term", "mouse", 10, 0, []string{"Wireless Mouse", "Desk Mat"}, 2},
+			{"no match", "piano", 10, 0, nil, 0},
+			{"page", "keyboard", 1, 1, []string{"Desk Mat"}, 2},
+			{"past the end", "keyboard", 10, 2, nil, 2},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				matches, total := search(t, repo, tt.query, tt.limit, tt.offset)
+				assertMatches(t, matches, tt.want)
+				if total != tt.total {
+					t.Errorf("total = %d, want %d", total, tt.total)
+				}
+			})
+		}
+	})
+
+	t.Run("SearchAccents", func(t *testing.T) {
+		repo := newRepo(t)
+		for i, p := range []struct{ name, description string }{
+			{"Café Crème", "Espresso with steamed milk"},
+			{"Cafe Noir", "Plain black coffee"},
+			{"Crème Brûlée", "Caramelised custard"},
+		} {
+			product := newProduct(t, p.name, "food", domain.StatusActive, baseTime.Add(time.Duration(i)*time.Minute))
+			product.Description = p.description
+			create(t, repo, product)
+		}
+
+		// Accents are significant on every backend; only case is folded.
+		for query, want := range map[string][]string{
+			"cafe":   {"Cafe Noir"},
+			"café":   {"Café Crème"},
+			"CAFÉ":   {"Café Crème"},
+			"brûlée": {"Crème Brûlée"},
+			"brulee": nil,
+		} {
+			matches, _ := search(t, repo, query, 10, 0)
+			assertMatches(t, matches, want)
+		}
+	})
+
+	t.Run("SearchFollowsChanges", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		products := seedSearch(t, repo)
+
+		stand := products["Monitor Stand"]
+		stand.Description = "Leaves room for a keyboard underneath"
+		if err := repo.Update(ctx, stand); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		softDelete(t, repo, products["Desk Mat"], baseTime.Add(time.Hour))
+
+		matches, total := search(t, repo, "keyboard", 10, 0)
+		assertMatches(t, matches, []string{"Mechanical Keyboard", "Monitor Stand"})
+		if total != 2 {
+			t.Errorf("total = %d, want 2", total)
+		}
+	})
+}
+
+// seedSearch creates products for the search tests and returns them by name.
+func seedSearch(t *testing.T, repo domain.ProductRepository) map[string]*domain.Product {
+	t.Helper()
+
+	products := make(map[string]*domain.Product)
+	for i, p := range []struct{ name, description string }{
+		{"Mechanical Keyboard", "Tactile switches and a detachable cable"},
+		{"Desk Mat", "Fits a keyboard and a mouse side by side"},
+		{"Wireless Mouse", "Ergonomic grip"},
+		{"Monitor Stand", "Raises the screen to eye level"},
+	} {
+		product := newProduct(t, p.name, "office", domain.StatusActive, baseTime.Add(time.Duration(i)*time.Minute))
+		product.Description = p.description
+		create(t, repo, product)
+		products[p.name] = product
+	}
+	return products
+}
+
+func search(t *testing.T, repo domain.ProductRepository, query string, limit, offset int) ([]*domain.ProductMatch, int) {
+	t.Helper()
+
+	matches, total, err := repo.Search(context.Background(), domain.ProductSearch{
+		Terms:  domain.SearchTerms(query),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		t.Fatalf("Search(%q): %v", query, err)
+	}
+	return matches, total
+}
+
+// assertMatches checks the products found, in order, and that ranks are
+// positive and do not increase.
+func assertMatches(t *testing.T, matches []*domain.ProductMatch, want []string) {
+	t.Helper()
+
+	products := make([]*domain.Product, len(matches))
+	for i, match := range matches {
+		products[i] = match.Product
+		if match.Rank <= 0 {
+			t.Errorf("%q has rank %v, want a positive rank", match.Product.Name, match.Rank)
+		}
+		if i > 0 && match.Rank > matches[i-1].Rank {
+			t.Errorf("%q ranks %v, above %q at %v", match.Product.Name, match.Rank, matches[i-1].Product.Name, matches[i-1].Rank)
+		}
+	}
+	assertNames(t, products, want)
+}
+
+// sortCases are orderings of the seedCatalog products. "Apple Pie" and
+// "Cherry_Tart" share a price, "Apple Pie" and "Date Box" a stock level, and
+// names differ in case, so ties and case folding are both exercised.
+var sortCases = []struct {
+	name string
+	sort []domain.ProductSort
+	want []string
+}{
+	{"default", nil, []string{"Date Box", "Cherry_Tart", "Banana 100%", "apple juice", "Apple Pie"}},
+	{"name", []domain.ProductSort{{Field: domain.SortByName}}, []string{"apple juice", "Apple Pie", "Banana 100%", "Cherry_Tart", "Date Box"}},
+	{"-name", []domain.ProductSort{{Field: domain.SortByName, Desc: true}}, []string{"Date Box", "Cherry_Tart", "Banana 100%", "Apple Pie", "apple juice"}},
+	{"price", []domain.ProductSort{{Field: domain.SortByPrice}}, []string{"apple juice", "Apple Pie", "Cherry_Tart", "Banana 100%", "Date Box"}},
+	{"-price", []domain.ProductSort{{Field: domain.SortByPrice, Desc: true}}, []string{"Date Box", "Banana 100%", "Cherry_Tart", "Apple Pie", "apple juice"}},
+	{"price,-created_at", []domain.ProductSort{{Field: domain.SortByPrice}, {Field: domain.SortByCreatedAt, Desc: true}}, []string{"apple juice", "Cherry_Tart", "Apple Pie", "Banana 100%", "Date Box"}},
+	{"stock,name", []domain.ProductSort{{Field: domain.SortByStock}, {Field: domain.SortByName}}, []string{"Apple Pie", "Date Box", "Banana 100%", "Cherry_Tart", "apple juice"}},
+	{"-stock", []domain.ProductSort{{Field: domain.SortByStock, Desc: true}}, []string{"apple juice", "Cherry_Tart", "Banana 100%", "Date Box", "Apple Pie"}},
+	{"updated_at", []domain.ProductSort{{Field: domain.SortByUpdatedAt}}, []string{"apple juice", "Cherry_Tart", "Banana 100%", "Apple Pie", "Date Box"}},
+	{"created_at", []domain.ProductSort{{Field: domain.SortByCreatedAt}}, []string{"Apple Pie", "apple juice", "Banana 100%", "Cherry_Tart", "Date Box"}},
+}
+
+// seed creates "Product 1" to "Product 5", one minute apart, alternating
+// between the books and games categories; product 1 is a draft, product 4 is
+// discontinued and the others are active.
+func seed(t *testing.T, repo domain.ProductRepository) {
+	t.Helper()
+
+	for i := 1; i <= 5; i++ {
+		category := "books"
+		if i%2 == 0 {
+			category = "games"
+		}
+		status := domain.StatusActive
+		switch i {
+		case 1:
+			status = domain.StatusDraft
+		case 4:
+			status = domain.StatusDiscontinued
+		}
+		create(t, repo, newProduct(t, fmt.Sprintf("Product %d", i), category, status, baseTime.Add(time.Duration(i)*time.Minute)))
+	}
+}
+
+// seedCatalog creates five products that differ in every filterable and
+// sortable field. Their IDs are fixed, so ties on the sort fields are broken
+// in a known order.
+func seedCatalog(t *testing.T, repo domain.ProductRepository) {
+	t.Helper()
+
+	for i, p := range []struct {
+		name, price, currency string
+		stock                 int
+		category              string
+		updated               int
+	}{
+		{"Apple Pie", "5.00", "USD", 0, "books", 10},
+		{"apple juice", "2.50", "USD", 12, "games", 2},
+		{"Banana 100%", "12.00", "USD", 3, "books", 5},
+		{"Cherry_Tart", "5", "USD", 7, "toys", 4},
+		{"Date Box", "100.00", "EUR", 0, "games", 20},
+	} {
+		price, err := domain.ParsePrice(p.price, p.currency)
+		if err != nil {
+			t.Fatalf("ParsePrice(%q): %v", p.price, err)
+		}
+		product, err := domain.NewProduct(p.name, "", price, p.stock, p.category, "creator")
+		if err != nil {
+			t.Fatalf("NewProduct: %v", err)
+		}
+		product.ID = fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1)
+		product.CreatedAt = baseTime.Add(time.Duration(i+1) * time.Minute)
+		product.UpdatedAt = baseTime.Add(time.Duration(p.updated) * time.Minute)
+		create(t, repo, product)
+	}
+}
+
+func newProduct(t *testing.T, name, category string, status domain.ProductStatus, createdAt time.Time) *domain.Product {
+	t.Helper()
+
+	product, err := domain.NewProduct(name, "", mustPrice(t, "19.99"), 10, category, "creator")
+	if err != nil {
+		t.Fatalf("NewProduct: %v", err)
+	}
+	product.Status = status
+	product.CreatedAt = createdAt
+	product.UpdatedAt = createdAt
+	return product
+}
+
+func mustPrice(t *testing.T, amount string) domain.Price {
+	t.Helper()
+
+	price, err := domain.ParsePrice(amount, "USD")
+	if err != nil {
+		t.Fatalf("ParsePrice(%q): %v", amount, err)
+	}
+	return price
+}
+
+func create(t *testing.T, repo domain.ProductRepository, product *domain.Product) {
+	t.Helper()
+
+	if err := repo.Create(context.Background(), product); err != nil {
+		t.Fatalf("Create %q: %v", product.Name, err)
+	}
+}
+
+// softDelete deletes product as of at and saves it.
+func softDelete(t *testing.T, repo domain.ProductRepository, product *domain.Product, at time.Time) {
+	t.Helper()
+
+	if err := product.Delete("remover"); err != nil {
+		t.Fatalf("Delete %q: %v", product.Name, err)
+	}
+	product.DeletedAt = &at
+	product.UpdatedAt = at
+	if err := repo.Update(context.Background(), product); err != nil {
+		t.Fatalf("Update %q: %v", product.Name, err)
+	}
+}
+
+func findAll(t *testing.T, repo domain.ProductRepository, filters domain.ProductFilters) []*domain.Product {
+	t.Helper()
+
+	products, err := repo.FindAll(context.Background(), filters)
+	if err != nil {
+		t.Fatalf("FindAll(%+v): %v", filters, err)
+	}
+	return products
+}
+
+func assertNames(t *testing.T, products []*domain.Product, want []string) {
+	t.Helper()
+
+	got := make([]string, len(products))
+	for i, product := range products {
+		got[i] = product.Name
+	}
+
+	mismatch := len(got) != len(want)
+	for i := 0; !mismatch && i < len(got); i++ {
+		mismatch = got[i] != want[i]
+	}
+	if mismatch {
+		t.Fatalf("got products %q, want %q", got, want)
+	}
+}
+
+func assertEqual(t *testing.T, got, want *domain.Product) {
+	t.Helper()
+
+	checks := []struct {
+		field     string
+		got, want interface{}
+	}{
+		{"ID", got.ID, want.ID},
+		{"Name", got.Name, want.Name},
+		{"Description", got.Description, want.Description},
+		{"Price", got.Price.String(), want.Price.String()},
+		{"Currency", got.Price.Currency().Code(), want.Price.Currency().Code()},
+		{"Stock", got.Stock, want.Stock},
+		{"Reserved", got.Reserved, want.Reserved},
+		{"Category", got.Category, want.Category},
+		{"Status", got.Status, want.Status},
+		{"CreatedBy", got.CreatedBy, want.CreatedBy},
+		{"UpdatedBy", got.UpdatedBy, want.UpdatedBy},
+		{"Version", got.Version, want.Version},
+		{"DeletedBy", got.DeletedBy, want.DeletedBy},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+		}
+	}
+
+	if !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, want.CreatedAt)
+	}
+	if !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("UpdatedAt = %v, want %v", got.UpdatedAt, want.UpdatedAt)
+	}
+	if (got.DeletedAt == nil) != (want.DeletedAt == nil) || (got.DeletedAt != nil && !got.DeletedAt.Equal(*want.DeletedAt)) {
+		t.Errorf("DeletedAt = %v, want %v", got.DeletedAt, want.DeletedAt)
+	}
+}
@@ -0,0 +1,149 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-architecture/internal/product/domain"
+)
+
+// MovementFactory returns an empty stock ledger and the repository holding
+// the products it refers to. It is called once per subtest.
+type MovementFactory func(t *testing.T) (domain.ProductRepository, domain.StockMovementRepository)
+
+// TestStockMovementRepository runs the ledger contract against the
+// repositories returned by newRepos.
+func TestStockMovementRepository(t *testing.T, newRepos MovementFactory) {
+	t.Run("EmptyLedger", func(t *testing.T) {
+		products, movements := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+
+		got := findMovements(t, movements, domain.StockMovementFilters{ProductID: product.ID, Limit: 10})
+		if len(got) != 0 {
+			t.Fatalf("FindAll of an empty ledger returned %d movements", len(got))
+		}
+		if count, err := movements.Count(ctx, product.ID); err != nil || count != 0 {
+			t.Fatalf("Count = %d, %v; want 0", count, err)
+		}
+		if balance, err := movements.Balance(ctx, product.ID); err != nil || balance != 0 {
+			t.Fatalf("Balance = %d, %v; want 0", balance, err)
+		}
+	})
+
+	t.Run("CreateAndFindAll", func(t *testing.T) {
+		products, movements := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		other := newProduct(t, "Mouse", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+		create(t, products, other)
+
+		initial := newMovement(t, product, 10, domain.ReasonInitial, "", baseTime)
+		sale := newMovement(t, product, -3, domain.ReasonSale, "order 42", baseTime.Add(time.Hour))
+		restock := newMovement(t, product, 5, domain.ReasonRestock, "", baseTime.Add(2*time.Hour))
+		restock.CreatedBy = ""
+		for _, movement := range []*domain.StockMovement{initial, sale, restock, newMovement(t, other, 7, domain.ReasonInitial, "", baseTime)} {
+			if err := movements.Create(ctx, movement); err != nil {
+				t.Fatalf("Create %s: %v", movement.Reason, err)
+			}
+		}
+
+		got := findMovements(t, movements, domain.StockMovementFilters{ProductID: product.ID, Limit: 10})
+		if len(got) != 3 {
+			t.Fatalf("FindAll returned %d movements, want 3", len(got))
+		}
+		for i, want := range []*domain.StockMovement{restock, sale, initial} {
+			assertMovement(t, got[i], want)
+		}
+
+		page := findMovements(t, movements, domain.StockMovementFilters{ProductID: product.ID, Limit: 1, Offset: 1})
+		if len(page) != 1 || page[0].ID != sale.ID {
+			t.Fatalf("second page of one = %v, want the sale", page)
+		}
+
+		if count, err := movements.Count(ctx, product.ID); err != nil || count != 3 {
+			t.Fatalf("Count = %d, %v; want 3", count, err)
+		}
+		if balance, err := movements.Balance(ctx, product.ID); err != nil || balance != 12 {
+			t.Fatalf("Balance = %d, %v; want 12", balance, err)
+		}
+	})
+
+	t.Run("TiesOrderedByID", func(t *testing.T) {
+		products, movements := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+
+		ids := []string{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000003", "00000000-0000-0000-0000-000000000002"}
+		for _, id := range ids {
+			movement := newMovement(t, product, 1, domain.ReasonRestock, "", baseTime)
+			movement.ID = id
+			if err := movements.Create(ctx, movement); err != nil {
+				t.Fatalf("Create %s: %v", id, err)
+			}
+		}
+
+		got := findMovements(t, movements, domain.StockMovementFilters{ProductID: product.ID, Limit: 10})
+		want := []string{ids[1], ids[2], ids[0]}
+		for i := range want {
+			if i >= len(got) || got[i].ID != want[i] {
+				t.Fatalf("movement %d is not %s", i, want[i])
+			}
+		}
+	})
+}
+
+// newMovement returns a movement of product created at the given time.
+func newMovement(t *testing.T, product *domain.Product, quantity int, reason domain.MovementReason, note string, createdAt time.Time) *domain.StockMovement {
+	t.Helper()
+
+	movement, err := domain.NewStockMovement(product, quantity, reason, note, "clerk")
+	if err != nil {
+		t.Fatalf("NewStockMovement: %v", err)
+	}
+	movement.CreatedAt = createdAt
+	return movement
+}
+
+func findMovements(t *testing.T, repo domain.StockMovementRepository, filters domain.StockMovementFilters) []*domain.StockMovement {
+	t.Helper()
+
+	movements, err := repo.FindAll(context.Background(), filters)
+	if err != nil {
+		t.Fatalf("FindAll(%+v): %v", filters, err)
+	}
+	return movements
+}
+
+func assertMovement(t *testing.T, got, want *domain.StockMovement) {
+	t.Helper()
+
+	checks := []struct {
+		field     string
+		got, want interface{}
+	}{
+		{"ID", got.ID, want.ID},
+		{"ProductID", got.ProductID, want.ProductID},
+		{"Quantity", got.Quantity, want.Quantity},
+		{"Reason", got.Reason, want.Reason},
+		{"StockAfter", got.StockAfter, want.StockAfter},
+		{"Note", got.Note, want.Note},
+		{"CreatedBy", got.CreatedBy, want.CreatedBy},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s %s = %v, want %v", want.Reason, c.field, c.got, c.want)
+		}
+	}
+
+	if !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("%s CreatedAt = %v, want %v", want.Reason, got.CreatedAt, want.CreatedAt)
+	}
+}
@@ -0,0 +1,336 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-architecture/internal/product/domain"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+// ReservationFactory returns an empty reservation repository and the
+// repository holding the products it reserves. It is called once per
+// subtest.
+type ReservationFactory func(t *testing.T) (domain.ProductRepository, domain.StockReservationRepository)
+
+// TestStockReservationRepository runs the reservation contract against the
+// repositories returned by newRepos.
+func TestStockReservationRepository(t *testing.T, newRepos ReservationFactory) {
+	t.Run("Reserve", func(t *testing.T) {
+		products, reservations := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+
+		want := newReservation(t, product, 4, baseTime.Add(time.Hour))
+		reserve(t, reservations, want)
+
+		got, err := reservations.FindByID(ctx, want.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		assertReservation(t, got, want)
+
+		assertStock(t, products, product.ID, 10, 4, 2)
+	})
+
+	t.Run("ReserveRefused", func(t *testing.T) {
+		products, reservations := newRepos(t)
+		ctx := context.Background()
+
+		active := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		draft := newProduct(t, "Mouse", "peripherals", domain.StatusDraft, baseTime)
+		deleted := newProduct(t, "Trackball", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, active)
+		create(t, products, draft)
+		create(t, products, deleted)
+		softDelete(t, products, deleted, baseTime)
+
+		missing := newProduct(t, "Webcam", "peripherals", domain.StatusActive, baseTime)
+
+		cases := []struct {
+			name    string
+			product *domain.Product
+			want    error
+		}{
+			{"too many", active, domain.ErrInsufficientStock},
+			{"draft", draft, domain.ErrProductNotOnSale},
+			{"deleted", deleted, apperrors.ErrNotFound},
+			{"missing", missing, apperrors.ErrNotFound},
+		}
+		for _, c := range cases {
+			err := reservations.Reserve(ctx, newReservation(t, c.product, 11, baseTime.Add(time.Hour)))
+			if !errors.Is(err, c.want) {
+				t.Errorf("Reserve %s: got %v, want %v", c.name, err, c.want)
+			}
+		}
+
+		assertStock(t, products, active.ID, 10, 0, 1)
+	})
+
+	t.Run("ConcurrentReserve", func(t *testing.T) {
+		products, reservations := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 25)
+		for i := 0; i < 25; i++ {
+			reservation := newReservation(t, product, 1, baseTime.Add(time.Hour))
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- reservations.Reserve(ctx, reservation)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		reserved := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				reserved++
+			case !errors.Is(err, domain.ErrInsufficientStock):
+				t.Fatalf("Reserve: %v", err)
+			}
+		}
+		if reserved != 10 {
+			t.Fatalf("%d reservations of one unit succeeded, want the 10 in stock", reserved)
+		}
+
+		assertStock(t, products, product.ID, 10, 10, 11)
+	})
+
+	t.Run("Confirm", func(t *testing.T) {
+		products, reservations := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+
+		reservation := newReservation(t, product, 4, baseTime.Add(time.Hour))
+		reserve(t, reservations, reservation)
+
+		at := baseTime.Add(30 * time.Minute)
+		stock, err := reservations.Confirm(ctx, reservation, at)
+		if err != nil {
+			t.Fatalf("Confirm: %v", err)
+		}
+		if stock != 6 {
+			t.Fatalf("Confirm left %d in stock, want 6", stock)
+		}
+		if reservation.Status != domain.ReservationConfirmed {
+			t.Fatalf("Status after Confirm = %s, want confirmed", reservation.Status)
+		}
+
+		got, err := reservations.FindByID(ctx, reservation.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		assertReservation(t, got, reservation)
+		assertStock(t, products, product.ID, 6, 0, 3)
+
+		if _, err := reservations.Confirm(ctx, reservation, at); !errors.Is(err, domain.ErrReservationNotPending) {
+			t.Fatalf("second Confirm: got %v, want ErrReservationNotPending", err)
+		}
+		if err := reservations.Release(ctx, reservation, domain.ReservationReleased, at); !errors.Is(err, domain.ErrReservationNotPending) {
+			t.Fatalf("Release after Confirm: got %v, want ErrReservationNotPending", err)
+		}
+		assertStock(t, products, product.ID, 6, 0, 3)
+	})
+
+	t.Run("ConfirmExpired", func(t *testing.T) {
+		products, reservations := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+
+		reservation := newReservation(t, product, 4, baseTime.Add(time.Hour))
+		reserve(t, reservations, reservation)
+
+		if _, err := reservations.Confirm(ctx, reservation, baseTime.Add(time.Hour)); !errors.Is(err, domain.ErrReservationExpired) {
+			t.Fatalf("Confirm at expiry: got %v, want ErrReservationExpired", err)
+		}
+		assertStock(t, products, product.ID, 10, 4, 2)
+	})
+
+	t.Run("ConfirmDeleted", func(t *testing.T) {
+		products, reservations := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+
+		reservation := newReservation(t, product, 4, baseTime.Add(time.Hour))
+		reserve(t, reservations, reservation)
+
+		reserved, err := products.FindByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		softDelete(t, products, reserved, baseTime.Add(time.Minute))
+
+		at := baseTime.Add(30 * time.Minute)
+		if _, err := reservations.Confirm(ctx, reservation, at); !errors.Is(err, domain.ErrProductNotOnSale) {
+			t.Fatalf("Confirm of a deleted product: got %v, want ErrProductNotOnSale", err)
+		}
+		got, err := reservations.FindByID(ctx, reservation.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if got.Status != domain.ReservationPending {
+			t.Fatalf("Status after a refused Confirm = %s, want pending", got.Status)
+		}
+		assertStock(t, products, product.ID, 10, 4, 3)
+
+		// The units can still be released, by the buyer or the sweeper.
+		if err := reservations.Release(ctx, reservation, domain.ReservationReleased, at); err != nil {
+			t.Fatalf("Release: %v", err)
+		}
+		assertStock(t, products, product.ID, 10, 0, 4)
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		products, reservations := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+
+		reservation := newReservation(t, product, 4, baseTime.Add(time.Hour))
+		reserve(t, reservations, reservation)
+
+		if err := reservations.Release(ctx, reservation, domain.ReservationReleased, baseTime.Add(time.Minute)); err != nil {
+			t.Fatalf("Release: %v", err)
+		}
+
+		got, err := reservations.FindByID(ctx, reservation.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		assertReservation(t, got, reservation)
+		assertStock(t, products, product.ID, 10, 0, 3)
+
+		if err := reservations.Release(ctx, reservation, domain.ReservationReleased, baseTime.Add(time.Minute)); !errors.Is(err, domain.ErrReservationNotPending) {
+			t.Fatalf("second Release: got %v, want ErrReservationNotPending", err)
+		}
+	})
+
+	t.Run("FindExpired", func(t *testing.T) {
+		products, reservations := newRepos(t)
+		ctx := context.Background()
+
+		product := newProduct(t, "Keyboard", "peripherals", domain.StatusActive, baseTime)
+		create(t, products, product)
+
+		second := newReservation(t, product, 1, baseTime.Add(2*time.Hour))
+		first := newReservation(t, product, 1, baseTime.Add(time.Hour))
+		pending := newReservation(t, product, 1, baseTime.Add(3*time.Hour))
+		released := newReservation(t, product, 1, baseTime.Add(time.Hour))
+		for _, reservation := range []*domain.StockReservation{second, first, pending, released} {
+			reserve(t, reservations, reservation)
+		}
+		if err := reservations.Release(ctx, released, domain.ReservationReleased, baseTime); err != nil {
+			t.Fatalf("Release: %v", err)
+		}
+
+		at := baseTime.Add(2 * time.Hour)
+		for _, c := range []struct {
+			limit int
+			want  []*domain.StockReservation
+		}{
+			{10, []*domain.StockReservation{first, second}},
+			{1, []*domain.StockReservation{first}},
+		} {
+			got, err := reservations.FindExpired(ctx, at, c.limit)
+			if err != nil {
+				t.Fatalf("FindExpired: %v", err)
+			}
+			if len(got) != len(c.want) {
+				t.Fatalf("FindExpired(limit %d) returned %d reservations, want %d", c.limit, len(got), len(c.want))
+			}
+			for i := range c.want {
+				assertReservation(t, got[i], c.want[i])
+			}
+		}
+
+		if err := reservations.Release(ctx, first, domain.ReservationExpired, at); err != nil {
+			t.Fatalf("Release expired: %v", err)
+		}
+		assertStock(t, products, product.ID, 10, 2, 7)
+	})
+}
+
+// newReservation returns a reservation of product created at baseTime.
+func newReservation(t *testing.T, product *domain.Product, quantity int, expiresAt time.Time) *domain.StockReservation {
+	t.Helper()
+
+	reservation, err := domain.NewStockReservation(product.ID, quantity, time.Hour, "clerk")
+	if err != nil {
+		t.Fatalf("NewStockReservation: %v", err)
+	}
+	reservation.CreatedAt = baseTime
+	reservation.ExpiresAt = expiresAt
+	return reservation
+}
+
+func reserve(t *testing.T, repo domain.StockReservationRepository, reservation *domain.StockReservation) {
+	t.Helper()
+
+	if err := repo.Reserve(context.Background(), reservation); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+}
+
+// assertStock checks the stock, reserved count and version of a product.
+func assertStock(t *testing.T, repo domain.ProductRepository, id string, stock, reserved int, version int64) {
+	t.Helper()
+
+	product, err := repo.FindByIDIncludingDeleted(context.Background(), id)
+	if err != nil {
+		t.Fatalf("FindByIDIncludingDeleted: %v", err)
+	}
+	if product.Stock != stock || product.Reserved != reserved || product.Version != version {
+		t.Fatalf("stock %d, reserved %d, version %d; want %d, %d, %d",
+			product.Stock, product.Reserved, product.Version, stock, reserved, version)
+	}
+}
+
+func assertReservation(t *testing.T, got, want *domain.StockReservation) {
+	t.Helper()
+
+	checks := []struct {
+		field     string
+		got, want interface{}
+	}{
+		{"ID", got.ID, want.ID},
+		{"ProductID", got.ProductID, want.ProductID},
+		{"Quantity", got.Quantity, want.Quantity},
+		{"Status", got.Status, want.Status},
+		{"CreatedBy", got.CreatedBy, want.CreatedBy},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+		}
+	}
+
+	if !got.ExpiresAt.Equal(want.ExpiresAt) {
+		t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, want.ExpiresAt)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, want.CreatedAt)
+	}
+	if (got.ResolvedAt == nil) != (want.ResolvedAt == nil) ||
+		got.ResolvedAt != nil && !got.ResolvedAt.Equal(*want.ResolvedAt) {
+		t.Errorf("ResolvedAt = %v, want %v", got.ResolvedAt, want.ResolvedAt)
+	}
+}
@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+	"strings"
+
+	moderncsqlite "modernc.org/sqlite"
+)
+
+// SQLite's built-in LOWER only folds ASCII letters, so "É" would neither
+// match an "é" filter nor sort with it. Replace it with Go's Unicode case
+// folding, which the cursor values compared against LOWER(name) already
+// use, as do the other backends.
+func init() {
+	moderncsqlite.MustRegisterDeterministicScalarFunction("lower", 1, func(_ *moderncsqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch v := args[0].(type) {
+		case string:
+			return strings.ToLower(v), nil
+		case []byte:
+			return strings.ToLower(string(v)), nil
+		default:
+			return v, nil
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/product/domain"
+	productquery "go-architecture/internal/product/infra/query"
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+)
+
+type ProductRepository struct {
+	db database.Querier
+}
+
+func NewProductRepository(db *sqlx.DB) *ProductRepository {
+	return &ProductRepository{db: db}
+}
+
+type productModel struct {
+	ID          string         `db:"id"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Price       float64        `db:"price"`
+	Currency    string         `db:"currency"`
+	Stock       int            `db:"stock"`
+	Category    string         `db:"category"`
+	Active      bool           `db:"active"`
+	Status      string         `db:"status"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+	CreatedBy   sql.NullString `db:"created_by"`
+	UpdatedBy   sql.NullString `db:"updated_by"`
+	Version     int64          `db:"version"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
+	DeletedBy   sql.NullString `db:"deleted_by"`
+	Reserved    int            `db:"reserved"`
+}
+
+type searchModel struct {
+	productModel
+	Rank float64 `db:"rank"`
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	query := `
+		INSERT INTO products (` + productquery.ProductColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		product.ID,
+		product.Name,
+		product.Description,
+		product.Price.String(),
+		product.Price.Currency().Code(),
+		product.Stock,
+		product.Category,
+		product.IsActive(),
+		product.CreatedAt,
+		product.UpdatedAt,
+		database.NullString(product.CreatedBy),
+		database.NullString(product.UpdatedBy),
+		product.Version,
+		product.Status,
+		database.NullTime(product.DeletedAt),
+		database.NullString(product.DeletedBy),
+		product.Reserved,
+	)
+
+	return err
+}
+
+func (r *ProductRepository) FindByID(ctx context.Context, id string) (*domain.Product, error) {
+	return r.findByID(ctx, id, false)
+}
+
+func (r *ProductRepository) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Product, error) {
+	return r.findByID(ctx, id, true)
+}
+
+func (r *ProductRepository) findByID(ctx context.Context, id string, includeDeleted bool) (*domain.Product, error) {
+	query := `
+		SELECT ` + productquery.ProductColumns + `
+		FROM products
+		WHERE id = ?
+	`
+	if !includeDeleted {
+		query += `AND deleted_at IS NULL`
+	}
+
+	var model productModel
+	err := r.db.GetContext(ctx, &model, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model)
+}
+
+func (r *ProductRepository) FindAll(ctx context.Context, filters domain.ProductFilters) ([]*domain.Product, error) {
+	query, args := productquery.FindProducts(database.SQLite, filters)
+
+	var models []productModel
+	err := r.db.SelectContext(ctx, &models, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]*domain.Product, len(models))
+	for i, model := range models {
+		product, err := r.toDomain(&model)
+		if err != nil {
+			return nil, err
+		}
+		products[i] = product
+	}
+
+	return products, nil
+}
+
+func (r *ProductRepository) Count(ctx context.Context, filters domain.ProductFilters) (int, error) {
+	query, args := productquery.CountProducts(database.SQLite, filters)
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, args...)
+	return count, err
+}
+
+func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	query := `
+		UPDATE products
+		SET name = ?, description = ?, price = ?, currency = ?, stock = ?, category = ?, active = ?, status = ?,
+			updated_at = ?, updated_by = ?, deleted_at = ?, deleted_by = ?, version = version + 1
+		WHERE id = ? AND version = ?
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		product.Name,
+		product.Description,
+		product.Price.String(),
+		product.Price.Currency().Code(),
+		product.Stock,
+		product.Category,
+		product.IsActive(),
+		product.Status,
+		product.UpdatedAt,
+		database.NullString(product.UpdatedBy),
+		database.NullTime(product.DeletedAt),
+		database.NullString(product.DeletedBy),
+		product.ID,
+		product.Version,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return r.updateConflict(ctx, product.ID)
+	}
+
+	product.Version++
+	return nil
+}
+
+// updateConflict tells apart a missing row from a stale version after a
+// version-checked UPDATE matched no rows.
+func (r *ProductRepository) updateConflict(ctx context.Context, id string) error {
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM products WHERE id = ?)`, id); err != nil {
+		return err
+	}
+
+	if !exists {
+		return apperrors.ErrNotFound
+	}
+
+	return apperrors.ErrConcurrencyConflict
+}
+
+func (r *ProductRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	query := `DELETE FROM products WHERE deleted_at < ?`
+
+	result, err := r.db.ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
+
+func (r *ProductRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM products WHERE name = ?)`
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, name)
+	return exists, err
+}
+
+// Search queries the products_fts table. FTS5's bm25 is lower for better
+// matches, so it is negated, and name matches weigh ten times more.
+func (r *ProductRepository) Search(ctx context.Context, search domain.ProductSearch) ([]*domain.ProductMatch, int, error) {
+	match := matchExpression(search.Terms)
+
+	query := `
+		SELECT ` + productquery.ProductColumns + `, m.score AS rank
+		FROM products
+		JOIN (
+			SELECT id AS match_id, -bm25(products_fts, 0.0, 10.0, 1.0) AS score
+			FROM products_fts
+			WHERE products_fts MATCH ?
+		) AS m ON products.id = m.match_id
+		WHERE products.deleted_at IS NULL
+		ORDER BY m.score DESC, created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var models []searchModel
+	if err := r.db.SelectContext(ctx, &models, query, match, search.Limit, search.Offset); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM products_fts
+		JOIN products ON products.id = products_fts.id
+		WHERE products_fts MATCH ? AND products.deleted_at IS NULL
+	`
+	if err := r.db.GetContext(ctx, &total, countQuery, match); err != nil {
+		return nil, 0, err
+	}
+
+	matches := make([]*domain.ProductMatch, len(models))
+	for i, model := range models {
+		product, err := r.toDomain(&model.productModel)
+		if err != nil {
+			return nil, 0, err
+		}
+		matches[i] = &domain.ProductMatch{Product: product, Rank: model.Rank}
+	}
+
+	return matches, total, nil
+}
+
+// matchExpression requires every term as a word prefix, e.g.
+// "desk"* AND "lamp"*.
+func matchExpression(terms []string) string {
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		phrases[i] = `"` + term + `"*`
+	}
+	return strings.Join(phrases, " AND ")
+}
+
+func (r *ProductRepository) toDomain(model *productModel) (*domain.Product, error) {
+	// SQLite stores NUMERIC values as REAL; DECIMAL(10,2) amounts round-trip
+	// exactly through their shortest decimal representation.
+	price, err := domain.ParsePrice(strconv.FormatFloat(model.Price, 'f', -1, 64), model.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var deletedAt *time.Time
+	if model.DeletedAt.Valid {
+		t := model.DeletedAt.Time
+		deletedAt = &t
+	}
+
+	return &domain.Product{
+		ID:          model.ID,
+		Name:        model.Name,
+		Description: model.Description,
+		Price:       price,
+		Stock:       model.Stock,
+		Category:    model.Category,
+		Status:      domain.ProductStatus(model.Status),
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+		CreatedBy:   model.CreatedBy.String,
+		UpdatedBy:   model.UpdatedBy.String,
+		Version:     model.Version,
+		DeletedAt:   deletedAt,
+		DeletedBy:   model.DeletedBy.String,
+		Reserved:    model.Reserved,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/product/infra/repotest"
+	"go-architecture/internal/product/infra/sqlite"
+	"go-architecture/internal/shared/config"
+	"go-architecture/internal/shared/database"
+	"go-architecture/internal/shared/logger"
+	"go-architecture/migrations"
+)
+
+func TestProductRepository(t *testing.T) {
+	repotest.TestProductRepository(t, func(t *testing.T) domain.ProductRepository {
+		return sqlite.NewProductRepository(openDB(t))
+	})
+}
+
+func TestStockMovementRepository(t *testing.T) {
+	repotest.TestStockMovementRepository(t, func(t *testing.T) (domain.ProductRepository, domain.StockMovementRepository) {
+		db := openDB(t)
+		return sqlite.NewProductRepository(db), sqlite.NewStockMovementRepository(db)
+	})
+}
+
+func TestStockReservationRepository(t *testing.T) {
+	repotest.TestStockReservationRepository(t, func(t *testing.T) (domain.ProductRepository, domain.StockReservationRepository) {
+		db := openDB(t)
+		return sqlite.NewProductRepository(db), sqlite.NewStockReservationRepository(db)
+	})
+}
+
+// openDB returns a fresh, migrated in-memory database.
+func openDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := database.Open(config.DatabaseConfig{Driver: database.DriverSQLite, DSN: ":memory:"}, true, logger.NewLogger())
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := database.Migrate(context.Background(), db, database.DriverSQLite, migrations.FS); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return db
+}
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/product/domain"
+	productquery "go-architecture/internal/product/infra/query"
+	"go-architecture/internal/shared/database"
+)
+
+type StockMovementRepository struct {
+	db database.Querier
+}
+
+func NewStockMovementRepository(db *sqlx.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: db}
+}
+
+type stockMovementModel struct {
+	ID         string         `db:"id"`
+	ProductID  string         `db:"product_id"`
+	Quantity   int            `db:"quantity"`
+	Reason     string         `db:"reason"`
+	StockAfter int            `db:"stock_after"`
+	Note       sql.NullString `db:"note"`
+	CreatedAt  time.Time      `db:"created_at"`
+	CreatedBy  sql.NullString `db:"created_by"`
+}
+
+func (r *StockMovementRepository) Create(ctx context.Context, movement *domain.StockMovement) error {
+	query := `
+		INSERT INTO stock_movements (` + productquery.StockMovementColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		movement.ID,
+		movement.ProductID,
+		movement.Quantity,
+		movement.Reason,
+		movement.StockAfter,
+		database.NullString(movement.Note),
+		movement.CreatedAt,
+		database.NullString(movement.CreatedBy),
+	)
+
+	return err
+}
+
+func (r *StockMovementRepository) FindAll(ctx context.Context, filters domain.StockMovementFilters) ([]*domain.StockMovement, error) {
+	query, args := productquery.FindStockMovements(database.SQLite, filters)
+
+	var models []stockMovementModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, err
+	}
+
+	movements := make([]*domain.StockMovement, len(models))
+	for i := range models {
+		movements[i] = r.toDomain(&models[i])
+	}
+
+	return movements, nil
+}
+
+func (r *StockMovementRepository) Count(ctx context.Context, productID string) (int, error) {
+	query := `SELECT COUNT(*) FROM stock_movements WHERE product_id = ?`
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, productID)
+	return count, err
+}
+
+func (r *StockMovementRepository) Balance(ctx context.Context, productID string) (int, error) {
+	query := `SELECT COALESCE(SUM(quantity), 0) FROM stock_movements WHERE product_id = ?`
+
+	var balance int
+	err := r.db.GetContext(ctx, &balance, query, productID)
+	return balance, err
+}
+
+func (r *StockMovementRepository) toDomain(model *stockMovementModel) *domain.StockMovement {
+	return &domain.StockMovement{
+		ID:         model.ID,
+		ProductID:  model.ProductID,
+		Quantity:   model.Quantity,
+		Reason:     domain.MovementReason(model.Reason),
+		StockAfter: model.StockAfter,
+		Note:       model.Note.String,
+		CreatedAt:  model.CreatedAt,
+		CreatedBy:  model.CreatedBy.String,
+	}
+}
@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/product/application"
+	"go-architecture/internal/product/domain"
+	"go-architecture/internal/shared/database"
+)
+
+type UnitOfWork struct {
+	db *sqlx.DB
+}
+
+func NewUnitOfWork(db *sqlx.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos application.Repositories) error) error {
+	return database.RunInTx(ctx, u.db, func(tx *sqlx.Tx) error {
+		return fn(ctx, &txRepositories{tx: tx})
+	})
+}
+
+// txRepositories hands out repositories bound to one transaction.
+type txRepositories struct {
+	tx *sqlx.Tx
+}
+
+func (r *txRepositories) Products() domain.ProductRepository {
+	return &ProductRepository{db: r.tx}
+}
+
+func (r *txRepositories) StockMovements() domain.StockMovementRepository {
+	return &StockMovementRepository{db: r.tx}
+}
+
+func (r *txRepositories) StockReservations() domain.StockReservationRepository {
+	return &StockReservationRepository{db: r.tx}
+}
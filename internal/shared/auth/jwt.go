@@ -4,35 +4,100 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Claims are the access token claims. The user ID is the "sub" claim.
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	Email    string   `json:"email,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+	TenantID string   `json:"tenant_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates a signed JWT using HMAC SHA256
-func GenerateToken(userID, email, role, secret string, hours int) (string, time.Time, error) {
+// Principal returns the caller described by verified claims.
+func (c *Claims) Principal() *Principal {
+	p := &Principal{
+		UserID:   c.Subject,
+		Email:    c.Email,
+		Roles:    c.Roles,
+		Scopes:   c.Scopes,
+		TenantID: c.TenantID,
+		TokenID:  c.ID,
+	}
+	if c.ExpiresAt != nil {
+		p.ExpiresAt = c.ExpiresAt.Time
+	}
+	return p
+}
+
+// IssuedToken is a signed access token with its ID (jti) and expiry, which
+// are needed to revoke it later.
+type IssuedToken struct {
+	Token     string
+	ID        string
+	ExpiresAt time.Time
+}
+
+// IssuerOptions are the registered claims stamped on every issued token.
+type IssuerOptions struct {
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+}
+
+// GenerateToken creates a JWT signed with the given key
+func GenerateToken(principal Principal, key *Key, opts IssuerOptions) (*IssuedToken, error) {
 	now := time.Now().UTC()
-	exp := now.Add(time.Duration(hours) * time.Hour)
+	exp := now.Add(opts.TTL)
+	id := uuid.New().String()
 
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		Email:    principal.Email,
+		Roles:    principal.Roles,
+		Scopes:   principal.Scopes,
+		TenantID: principal.TenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Issuer:    opts.Issuer,
+			Subject:   principal.UserID,
+			Audience:  audience(opts.Audience),
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(exp),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString([]byte(secret))
+	signed, err := key.sign(claims)
 	if err != nil {
-		return "", time.Time{}, err
+		return nil, err
+	}
+	return &IssuedToken{Token: signed, ID: id, ExpiresAt: exp}, nil
+}
+
+// TokenIssuer issues access tokens with a fixed lifetime, signed with the
+// current signing key of a KeySet.
+type TokenIssuer struct {
+	keys *KeySet
+	opts IssuerOptions
+}
+
+func NewTokenIssuer(keys *KeySet, opts IssuerOptions) *TokenIssuer {
+	return &TokenIssuer{keys: keys, opts: opts}
+}
+
+func (i *TokenIssuer) Issue(principal Principal) (*IssuedToken, error) {
+	return GenerateToken(principal, i.keys.SigningKey(), i.opts)
+}
+
+func (i *TokenIssuer) TTL() time.Duration {
+	return i.opts.TTL
+}
+
+func audience(aud string) jwt.ClaimStrings {
+	if aud == "" {
+		return nil
 	}
-	return signed, exp, nil
+	return jwt.ClaimStrings{aud}
 }
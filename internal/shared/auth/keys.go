@@ -0,0 +1,302 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnknownSigningKey = errors.New("token signed with an unknown key")
+	ErrAlgorithmMismatch = errors.New("token algorithm does not match its key")
+)
+
+// Key is a JWT signing or verification key. Asymmetric keys are identified
+// by their RFC 7638 thumbprint, which is used as the "kid" header.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	// signKey is nil for verification-only keys.
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet holds the key used to sign new tokens and every key accepted when
+// verifying, so that keys can be rotated without invalidating live tokens.
+type KeySet struct {
+	signing      *Key
+	verification map[string]*Key
+}
+
+// NewHMACKeySet returns a key set that signs and verifies with a shared
+// HS256 secret. HMAC keys are never published in the JWKS.
+func NewHMACKeySet(secret string) *KeySet {
+	key := &Key{
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+	return &KeySet{
+		signing:      key,
+		verification: map[string]*Key{"": key},
+	}
+}
+
+// LoadKeySet reads a PEM private key used for signing and any number of
+// additional PEM keys (public or private) that are accepted for verification,
+// typically the previous signing keys during a rotation.
+func LoadKeySet(signingKeyFile string, verificationKeyFiles []string) (*KeySet, error) {
+	signing, err := loadKeyFile(signingKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: %w", signingKeyFile, err)
+	}
+	if signing.signKey == nil {
+		return nil, fmt.Errorf("signing key %s: a private key is required", signingKeyFile)
+	}
+
+	ks := &KeySet{
+		signing:      signing,
+		verification: map[string]*Key{signing.ID: signing},
+	}
+
+	for _, file := range verificationKeyFiles {
+		key, err := loadKeyFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("verification key %s: %w", file, err)
+		}
+		ks.verification[key.ID] = key
+	}
+
+	return ks, nil
+}
+
+// SigningKey returns the key used to sign new tokens.
+func (ks *KeySet) SigningKey() *Key {
+	return ks.signing
+}
+
+// Algorithms returns the distinct algorithms of the verification keys.
+func (ks *KeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	var algs []string
+	for _, key := range ks.verification {
+		alg := key.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// Keyfunc resolves the verification key for a parsed token from its "kid"
+// header and rejects tokens whose algorithm differs from the key's.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.verification[kid]
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrAlgorithmMismatch
+	}
+
+	return key.verifyKey, nil
+}
+
+// sign signs claims with the key and sets the "kid" header.
+func (k *Key) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.Method, claims)
+	if k.ID != "" {
+		token.Header["kid"] = k.ID
+	}
+	return token.SignedString(k.signKey)
+}
+
+func loadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newKey(parsed)
+}
+
+func newKey(parsed interface{}) (*Key, error) {
+	key := &Key{}
+
+	var public crypto.PublicKey
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.signKey, public = k, &k.PublicKey
+	case *ecdsa.PrivateKey:
+		key.signKey, public = k, &k.PublicKey
+	case ed25519.PrivateKey:
+		key.signKey, public = k, k.Public()
+	default:
+		public = parsed
+	}
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			key.Method = jwt.SigningMethodES256
+		case elliptic.P384():
+			key.Method = jwt.SigningMethodES384
+		case elliptic.P521():
+			key.Method = jwt.SigningMethodES512
+		default:
+			return nil, errors.New("unsupported elliptic curve")
+		}
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", public)
+	}
+
+	key.verifyKey = public
+
+	jwk, err := publicJWK(public)
+	if err != nil {
+		return nil, err
+	}
+	key.ID = jwk.thumbprint()
+
+	return key, nil
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set as served from /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public verification keys. Symmetric keys are omitted.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+
+	for _, key := range ks.verification {
+		if key.ID == "" {
+			continue
+		}
+		jwk, err := publicJWK(key.verifyKey)
+		if err != nil {
+			continue
+		}
+		jwk.Kid = key.ID
+		jwk.Use = "sig"
+		jwk.Alg = key.Method.Alg()
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+func publicJWK(public crypto.PublicKey) (JWK, error) {
+	enc := base64.RawURLEncoding
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   enc.EncodeToString(pub.N.Bytes()),
+			E:   enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kty: "EC",
+			Crv: pub.Curve.Params().Name,
+			X:   enc.EncodeToString(pub.X.FillBytes(make([]byte, size))),
+			Y:   enc.EncodeToString(pub.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   enc.EncodeToString(pub),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("unsupported key type %T", public)
+	}
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint: the SHA-256 of the
+// required members serialized in lexicographic order.
+func (j JWK) thumbprint() string {
+	var members []string
+	switch j.Kty {
+	case "RSA":
+		members = []string{`"e":` + quote(j.E), `"kty":"RSA"`, `"n":` + quote(j.N)}
+	case "EC":
+		members = []string{`"crv":` + quote(j.Crv), `"kty":"EC"`, `"x":` + quote(j.X), `"y":` + quote(j.Y)}
+	case "OKP":
+		members = []string{`"crv":` + quote(j.Crv), `"kty":"OKP"`, `"x":` + quote(j.X)}
+	}
+
+	sum := sha256.Sum256([]byte("{" + strings.Join(members, ",") + "}"))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+// The RSA key of RFC 7638 section 3.1 and the Ed25519 key of RFC 8037
+// appendix A, with the thumbprints the RFCs compute for them.
+const (
+	rfc7638N          = "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"
+	rfc7638Thumbprint = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	rfc8037X          = "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+	rfc8037Thumbprint = "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+)
+
+func decodeBase64URL(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+	return b
+}
+
+func rfcKeys(t *testing.T) (rsaKey, edKey *Key) {
+	t.Helper()
+
+	rsaKey, err := newKey(&rsa.PublicKey{N: new(big.Int).SetBytes(decodeBase64URL(t, rfc7638N)), E: 65537})
+	if err != nil {
+		t.Fatalf("newKey(RSA): %v", err)
+	}
+	edKey, err = newKey(ed25519.PublicKey(decodeBase64URL(t, rfc8037X)))
+	if err != nil {
+		t.Fatalf("newKey(Ed25519): %v", err)
+	}
+	return rsaKey, edKey
+}
+
+func TestKeyIDIsRFC7638Thumbprint(t *testing.T) {
+	rsaKey, edKey := rfcKeys(t)
+
+	if rsaKey.ID != rfc7638Thumbprint {
+		t.Errorf("RSA kid %q, want %q", rsaKey.ID, rfc7638Thumbprint)
+	}
+	if rsaKey.Method.Alg() != "RS256" {
+		t.Errorf("RSA alg %s, want RS256", rsaKey.Method.Alg())
+	}
+	if edKey.ID != rfc8037Thumbprint {
+		t.Errorf("Ed25519 kid %q, want %q", edKey.ID, rfc8037Thumbprint)
+	}
+	if edKey.Method.Alg() != "EdDSA" {
+		t.Errorf("Ed25519 alg %s, want EdDSA", edKey.Method.Alg())
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, edKey := rfcKeys(t)
+
+	got, err := json.Marshal(keySetOf(rsaKey, edKey).JWKS())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"keys":[` +
+		`{"kty":"RSA","kid":"` + rfc7638Thumbprint + `","use":"sig","alg":"RS256","n":"` + rfc7638N + `","e":"AQAB"},` +
+		`{"kty":"OKP","kid":"` + rfc8037Thumbprint + `","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"` + rfc8037X + `"}` +
+		`]}`
+	if string(got) != want {
+		t.Fatalf("JWKS\n got %s\nwant %s", got, want)
+	}
+
+	if keys := NewHMACKeySet("secret").JWKS().Keys; len(keys) != 0 {
+		t.Fatalf("HMAC key set published %v", keys)
+	}
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Principal is the authenticated caller of a request. It is built from a
+// verified token by the middleware and travels in the request's
+// context.Context so the application layer can authorize and audit changes.
+type Principal struct {
+	UserID   string
+	Email    string
+	Roles    []string
+	Scopes   []string
+	TenantID string
+
+	// TokenID and ExpiresAt identify the access token the principal
+	// authenticated with; they are empty for principals built otherwise.
+	TokenID   string
+	ExpiresAt time.Time
+	// APIKeyID is set when the principal authenticated with an API key.
+	APIKeyID string
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return contains(p.Roles, role)
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored by WithPrincipal.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// ActorID returns the user ID of the principal in ctx, or "" for
+// unauthenticated calls. It is what gets recorded in created_by/updated_by.
+func ActorID(ctx context.Context) string {
+	if p, ok := PrincipalFromContext(ctx); ok {
+		return p.UserID
+	}
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
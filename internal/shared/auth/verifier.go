@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Causes reported when a token is rejected, so clients can tell an expired
+// token (refresh it) from one that will never be accepted.
+const (
+	CauseMalformed           = "malformed"
+	CauseExpired             = "expired"
+	CauseNotYetValid         = "not_yet_valid"
+	CauseInvalidIssuer       = "invalid_issuer"
+	CauseInvalidAudience     = "invalid_audience"
+	CauseInvalidSignature    = "invalid_signature"
+	CauseAlgorithmNotAllowed = "algorithm_not_allowed"
+	CauseUnknownKey          = "unknown_key"
+	CauseInvalidClaims       = "invalid_claims"
+)
+
+var ErrAlgorithmNotAllowed = errors.New("token algorithm is not allowed")
+
+// VerifierOptions configures the claim checks applied to every token.
+type VerifierOptions struct {
+	Issuer   string
+	Audience string
+	// Algorithms is the allow-list of "alg" values. When empty, the
+	// algorithms of the key set's keys are allowed.
+	Algorithms []string
+	// Leeway tolerates clock skew when checking exp, nbf and iat.
+	Leeway time.Duration
+}
+
+// Verifier parses tokens and enforces signature, algorithm, issuer,
+// audience and time-based claims.
+type Verifier struct {
+	keys       *KeySet
+	algorithms map[string]bool
+	parser     *jwt.Parser
+}
+
+func NewVerifier(keys *KeySet, opts VerifierOptions) *Verifier {
+	algs := opts.Algorithms
+	if len(algs) == 0 {
+		algs = keys.Algorithms()
+	}
+
+	allowed := make(map[string]bool, len(algs))
+	for _, alg := range algs {
+		if alg != "none" {
+			allowed[alg] = true
+		}
+	}
+
+	// The allow-list is enforced in keyfunc rather than with
+	// jwt.WithValidMethods so that a disallowed algorithm is reported as
+	// such instead of as a bad signature.
+	parserOpts := []jwt.ParserOption{
+		jwt.WithLeeway(opts.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+
+	return &Verifier{
+		keys:       keys,
+		algorithms: allowed,
+		parser:     jwt.NewParser(parserOpts...),
+	}
+}
+
+// Parse verifies tokenString and decodes its claims into claims.
+func (v *Verifier) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return v.parser.ParseWithClaims(tokenString, claims, v.keyfunc)
+}
+
+func (v *Verifier) keyfunc(token *jwt.Token) (interface{}, error) {
+	if !v.algorithms[token.Method.Alg()] {
+		return nil, ErrAlgorithmNotAllowed
+	}
+	return v.keys.Keyfunc(token)
+}
+
+// ErrorCause maps a Parse error to one of the Cause constants.
+func ErrorCause(err error) string {
+	switch {
+	case errors.Is(err, ErrAlgorithmNotAllowed):
+		return CauseAlgorithmNotAllowed
+	case errors.Is(err, ErrUnknownSigningKey), errors.Is(err, ErrAlgorithmMismatch):
+		return CauseUnknownKey
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return CauseMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return CauseInvalidSignature
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return CauseExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return CauseNotYetValid
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return CauseInvalidIssuer
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return CauseInvalidAudience
+	default:
+		return CauseInvalidClaims
+	}
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testIssuer = IssuerOptions{Issuer: "go-architecture", Audience: "api", TTL: time.Minute}
+
+func newECKey(t *testing.T, curve elliptic.Curve) *Key {
+	t.Helper()
+
+	private, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	key, err := newKey(private)
+	if err != nil {
+		t.Fatalf("newKey: %v", err)
+	}
+	return key
+}
+
+func keySetOf(signing *Key, others ...*Key) *KeySet {
+	ks := &KeySet{signing: signing, verification: map[string]*Key{signing.ID: signing}}
+	for _, key := range others {
+		ks.verification[key.ID] = key
+	}
+	return ks
+}
+
+// signWith signs the claims of a fresh token with method and secret, and
+// sets kid when it is not empty.
+func signWith(t *testing.T, method jwt.SigningMethod, secret interface{}, kid string) string {
+	t.Helper()
+
+	now := time.Now()
+	token := jwt.NewWithClaims(method, &Claims{RegisteredClaims: jwt.RegisteredClaims{
+		ID:        "jti",
+		Issuer:    testIssuer.Issuer,
+		Subject:   "user-1",
+		Audience:  audience(testIssuer.Audience),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func issue(t *testing.T, key *Key, opts IssuerOptions) string {
+	t.Helper()
+
+	issued, err := GenerateToken(Principal{UserID: "user-1", Roles: []string{"user"}}, key, opts)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	return issued.Token
+}
+
+func TestVerifierAcceptsIssuedToken(t *testing.T) {
+	previous := newECKey(t, elliptic.P256())
+	current := newECKey(t, elliptic.P256())
+	verifier := NewVerifier(keySetOf(current, previous), VerifierOptions{
+		Issuer:   testIssuer.Issuer,
+		Audience: testIssuer.Audience,
+	})
+
+	for name, key := range map[string]*Key{"current": current, "previous": previous} {
+		claims := &Claims{}
+		if _, err := verifier.Parse(issue(t, key, testIssuer), claims); err != nil {
+			t.Fatalf("Parse of a token signed with the %s key: %v", name, err)
+		}
+		if p := claims.Principal(); p.UserID != "user-1" || p.TokenID == "" {
+			t.Fatalf("principal %+v, want user-1 with a token ID", p)
+		}
+	}
+}
+
+func TestVerifierRejects(t *testing.T) {
+	key := newECKey(t, elliptic.P256())
+	p384 := newECKey(t, elliptic.P384())
+	stranger := newECKey(t, elliptic.P256())
+	hmac := NewHMACKeySet("secret").SigningKey()
+	public, err := x509.MarshalPKIXPublicKey(key.verifyKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+
+	// ES384 is allowed so that a P-384 signature under the P-256 key's kid
+	// reaches the key lookup; "none" is listed to show it is never allowed.
+	verifier := NewVerifier(keySetOf(key), VerifierOptions{
+		Issuer:     testIssuer.Issuer,
+		Audience:   testIssuer.Audience,
+		Algorithms: []string{"ES256", "ES384", "none"},
+	})
+
+	valid := issue(t, key, testIssuer)
+	tampered := valid[:strings.LastIndex(valid, ".")+1] + strings.Repeat("A", len(valid)-strings.LastIndex(valid, ".")-1)
+
+	expired := testIssuer
+	expired.TTL = -time.Second
+	otherIssuer := testIssuer
+	otherIssuer.Issuer = "someone-else"
+	otherAudience := testIssuer
+	otherAudience.Audience = "another-api"
+
+	cases := []struct {
+		name  string
+		token string
+		cause string
+	}{
+		{"alg none", signWith(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, key.ID), CauseAlgorithmNotAllowed},
+		{"HS256 signed with the public key", signWith(t, jwt.SigningMethodHS256, public, key.ID), CauseAlgorithmNotAllowed},
+		{"HS256 key outside the allow-list", issue(t, hmac, testIssuer), CauseAlgorithmNotAllowed},
+		{"algorithm differs from the key's", signWith(t, jwt.SigningMethodES384, p384.signKey, key.ID), CauseUnknownKey},
+		{"unknown kid", issue(t, stranger, testIssuer), CauseUnknownKey},
+		{"missing kid", signWith(t, jwt.SigningMethodES256, key.signKey, ""), CauseUnknownKey},
+		{"tampered signature", tampered, CauseInvalidSignature},
+		{"expired", issue(t, key, expired), CauseExpired},
+		{"wrong issuer", issue(t, key, otherIssuer), CauseInvalidIssuer},
+		{"wrong audience", issue(t, key, otherAudience), CauseInvalidAudience},
+		{"malformed", "not.a.token", CauseMalformed},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := verifier.Parse(c.token, &Claims{})
+			if err == nil {
+				t.Fatal("Parse accepted the token")
+			}
+			if cause := ErrorCause(err); cause != c.cause {
+				t.Fatalf("cause %q (%v), want %q", cause, err, c.cause)
+			}
+		})
+	}
+}
+
+func TestVerifierLeeway(t *testing.T) {
+	key := newECKey(t, elliptic.P256())
+	expired := testIssuer
+	expired.TTL = -time.Second
+	token := issue(t, key, expired)
+
+	if _, err := NewVerifier(keySetOf(key), VerifierOptions{}).Parse(token, &Claims{}); ErrorCause(err) != CauseExpired {
+		t.Fatalf("Parse without leeway: got %v, want an expired token", err)
+	}
+	if _, err := NewVerifier(keySetOf(key), VerifierOptions{Leeway: time.Minute}).Parse(token, &Claims{}); err != nil {
+		t.Fatalf("Parse within the leeway: %v", err)
+	}
+}
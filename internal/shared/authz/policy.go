@@ -0,0 +1,95 @@
+package authz
+
+import (
+	"context"
+	"strings"
+
+	"go-architecture/internal/shared/auth"
+	"go-architecture/internal/shared/errors"
+)
+
+// Resources and actions. A permission is "resource:action", e.g.
+// "product:write".
+const (
+	ResourceProduct = "product"
+	ResourceUser    = "user"
+	ResourceAPIKey  = "api_key"
+
+	ActionRead   = "read"
+	ActionWrite  = "write"
+	ActionDelete = "delete"
+)
+
+// Wildcard grants every resource, or every action when used as the action
+// ("product:*").
+const Wildcard = "*"
+
+// Permission builds the "resource:action" permission string.
+func Permission(resource, action string) string {
+	return resource + ":" + action
+}
+
+// Policy maps roles to the permissions they grant.
+type Policy struct {
+	roles map[string]map[string]bool
+}
+
+// NewPolicy builds a policy from role -> permissions, as read from
+// config.AuthzConfig.
+func NewPolicy(rolePermissions map[string][]string) *Policy {
+	roles := make(map[string]map[string]bool, len(rolePermissions))
+	for role, permissions := range rolePermissions {
+		granted := make(map[string]bool, len(permissions))
+		for _, permission := range permissions {
+			granted[strings.TrimSpace(permission)] = true
+		}
+		roles[role] = granted
+	}
+	return &Policy{roles: roles}
+}
+
+// Allows reports whether the principal may perform action on resource: one
+// of its roles must grant the permission and, when the principal carries
+// scopes, one of them must cover it as well.
+func (p *Policy) Allows(principal *auth.Principal, action, resource string) bool {
+	granted := false
+	for _, role := range principal.Roles {
+		if covers(p.roles[role], action, resource) {
+			granted = true
+			break
+		}
+	}
+	if !granted {
+		return false
+	}
+
+	if len(principal.Scopes) == 0 {
+		return true
+	}
+	scopes := make(map[string]bool, len(principal.Scopes))
+	for _, scope := range principal.Scopes {
+		scopes[scope] = true
+	}
+	return covers(scopes, action, resource)
+}
+
+// Authorize checks the principal stored in ctx. It returns a 401 AppError
+// when the call is unauthenticated and a 403 one when it is not allowed.
+func (p *Policy) Authorize(ctx context.Context, action, resource string) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return errors.NewUnauthorizedError("User not authenticated")
+	}
+
+	if !p.Allows(principal, action, resource) {
+		return errors.NewForbiddenError("Missing permission " + Permission(resource, action))
+	}
+
+	return nil
+}
+
+func covers(granted map[string]bool, action, resource string) bool {
+	return granted[Wildcard] ||
+		granted[Permission(resource, Wildcard)] ||
+		granted[Permission(resource, action)]
+}
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestLoadConfigAutoMigrate(t *testing.T) {
+	cases := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"true", true},
+		{"1", true},
+		{"false", false},
+		{"not-a-bool", false},
+	}
+	for _, c := range cases {
+		t.Setenv("DB_AUTO_MIGRATE", c.value)
+
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig: %v", err)
+		}
+		if cfg.Database.AutoMigrate != c.want {
+			t.Errorf("DB_AUTO_MIGRATE=%q: AutoMigrate = %v, want %v", c.value, cfg.Database.AutoMigrate, c.want)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migrationFile matches "001_create_products_table.up.sql".
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// noTransaction, as the first line of a script, runs it outside a
+// transaction, for statements such as SQL Server's full-text DDL that refuse
+// to run inside one. Such a migration is not atomic.
+const noTransaction = "-- migrate:no-transaction"
+
+// nonWord is replaced by underscores in the names of new migrations.
+var nonWord = regexp.MustCompile(`\W+`)
+
+var schemaMigrationsDDL = map[string]string{
+	DriverMSSQL: `IF OBJECT_ID(N'dbo.schema_migrations', N'U') IS NULL
+CREATE TABLE dbo.schema_migrations (version NVARCHAR(32) NOT NULL PRIMARY KEY, name NVARCHAR(255) NOT NULL, checksum NVARCHAR(64) NOT NULL, applied_at DATETIME2 NOT NULL)`,
+	DriverPostgres: `CREATE TABLE IF NOT EXISTS schema_migrations (version VARCHAR(32) PRIMARY KEY, name VARCHAR(255) NOT NULL, checksum VARCHAR(64) NOT NULL, applied_at TIMESTAMP NOT NULL)`,
+	DriverSQLite:   `CREATE TABLE IF NOT EXISTS schema_migrations (version VARCHAR(32) PRIMARY KEY, name VARCHAR(255) NOT NULL, checksum VARCHAR(64) NOT NULL, applied_at TIMESTAMP NOT NULL)`,
+}
+
+// Migration is one versioned schema change. Checksum is the SHA-256 of the
+// up script and is recorded when the migration is applied, so that edits to
+// applied scripts are detected.
+type Migration struct {
+	Version  string
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationStatus is a migration together with its state in the database.
+type MigrationStatus struct {
+	Migration
+	// AppliedAt is nil for pending migrations.
+	AppliedAt *time.Time
+	// Modified reports that the up script changed after it was applied.
+	Modified bool
+}
+
+type appliedMigration struct {
+	Version   string    `db:"version"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// Migrator applies the migrations of one driver, read from the directory
+// named after the driver (e.g. "postgres/") in an fs.FS.
+type Migrator struct {
+	db         *sqlx.DB
+	driver     string
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations for driver from fsys.
+func NewMigrator(db *sqlx.DB, driver string, fsys fs.FS) (*Migrator, error) {
+	if _, ok := schemaMigrationsDDL[driver]; !ok {
+		return nil, fmt.Errorf("database: unsupported driver %q", driver)
+	}
+
+	dir, err := fs.Sub(fsys, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return nil, fmt.Errorf("load %s migrations: %w", driver, err)
+	}
+
+	return &Migrator{db: db, driver: driver, migrations: migrations}, nil
+}
+
+// Migrate applies every pending migration for driver and returns the ones
+// applied.
+func Migrate(ctx context.Context, db *sqlx.DB, driver string, fsys fs.FS) ([]Migration, error) {
+	m, err := NewMigrator(db, driver, fsys)
+	if err != nil {
+		return nil, err
+	}
+	return m.Up(ctx)
+}
+
+// Status lists every known migration in version order.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			statuses[i].AppliedAt = &appliedAt
+			statuses[i].Modified = row.Checksum != migration.Checksum
+		}
+	}
+
+	return statuses, nil
+}
+
+// Up applies the pending migrations in version order, each in its own
+// transaction. It refuses to run when an applied script was modified.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, status := range statuses {
+		if status.Modified {
+			return nil, fmt.Errorf("migration %s_%s was modified after it was applied", status.Version, status.Name)
+		}
+	}
+
+	var ran []Migration
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			continue
+		}
+
+		migration := status.Migration
+		err := m.run(ctx, migration.Up, `INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+			migration.Version, migration.Name, migration.Checksum, time.Now().UTC())
+		if err != nil {
+			return ran, fmt.Errorf("migration %s_%s: %w", migration.Version, migration.Name, err)
+		}
+		ran = append(ran, migration)
+	}
+
+	return ran, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first, and
+// returns the ones rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for i := len(statuses) - 1; i >= 0 && len(ran) < steps; i-- {
+		if statuses[i].AppliedAt == nil {
+			continue
+		}
+
+		migration := statuses[i].Migration
+		if migration.Down == "" {
+			return ran, fmt.Errorf("migration %s_%s has no down script", migration.Version, migration.Name)
+		}
+
+		err := m.run(ctx, migration.Down, `DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+		if err != nil {
+			return ran, fmt.Errorf("rollback %s_%s: %w", migration.Version, migration.Name, err)
+		}
+		ran = append(ran, migration)
+	}
+
+	return ran, nil
+}
+
+// run executes script and then the schema_migrations statement record, in
+// one transaction unless the script starts with noTransaction.
+func (m *Migrator) run(ctx context.Context, script, record string, args ...interface{}) error {
+	if strings.HasPrefix(script, noTransaction) {
+		if _, err := m.db.ExecContext(ctx, script); err != nil {
+			return err
+		}
+		_, err := m.db.ExecContext(ctx, m.db.Rebind(record), args...)
+		return err
+	}
+
+	return RunInTx(ctx, m.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, tx.Rebind(record), args...)
+		return err
+	})
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[string]appliedMigration, error) {
+	if _, err := m.db.ExecContext(ctx, schemaMigrationsDDL[m.driver]); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var rows []appliedMigration
+	if err := m.db.SelectContext(ctx, &rows, `SELECT version, checksum, applied_at FROM schema_migrations`); err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+
+	applied := make(map[string]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// loadMigrations pairs the up and down scripts of dir by version.
+func loadMigrations(dir fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(dir, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, name, direction := match[1], match[2], match[3]
+
+		script, err := fs.ReadFile(dir, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("version %s is used by both %q and %q", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			sum := sha256.Sum256(script)
+			migration.Up = string(script)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %s_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// CreateMigration writes empty up and down scripts for the next version in
+// every driver directory under dir and returns the paths written.
+func CreateMigration(dir, name string) ([]string, error) {
+	name = strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, errors.New("migration name is required")
+	}
+
+	drivers := make([]string, 0, len(schemaMigrationsDDL))
+	for driver := range schemaMigrationsDDL {
+		drivers = append(drivers, driver)
+	}
+	sort.Strings(drivers)
+
+	next := 1
+	for _, driver := range drivers {
+		entries, err := os.ReadDir(filepath.Join(dir, driver))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if match := migrationFile.FindStringSubmatch(entry.Name()); match != nil {
+				if version, _ := strconv.Atoi(match[1]); version >= next {
+					next = version + 1
+				}
+			}
+		}
+	}
+
+	var paths []string
+	for _, driver := range drivers {
+		if err := os.MkdirAll(filepath.Join(dir, driver), 0o755); err != nil {
+			return paths, err
+		}
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, driver, fmt.Sprintf("%03d_%s.%s.sql", next, name, direction))
+			header := fmt.Sprintf("-- %s (%s, %s)\n", strings.ReplaceAll(name, "_", " "), driver, direction)
+			if err := os.WriteFile(path, []byte(header), 0o644); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,177 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/shared/config"
+	"go-architecture/internal/shared/logger"
+)
+
+// Supported values of config.DatabaseConfig.Driver.
+const (
+	DriverMSSQL    = "mssql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// connector holds what differs between backends when connecting.
+type connector struct {
+	// sqlDriver is the database/sql driver name.
+	sqlDriver string
+	// prepareDSN adds the defaults a backend needs; development relaxes TLS
+	// for local servers.
+	prepareDSN func(dsn string, development bool) string
+	// redactDSN hides credentials so the DSN can be logged.
+	redactDSN func(dsn string) string
+	// explain turns common connection failures into actionable errors.
+	explain func(err error, dsn string) error
+	// tune adjusts the pool after the configured settings are applied.
+	tune func(db *sqlx.DB, dsn string)
+}
+
+var connectors = map[string]connector{
+	DriverMSSQL: {
+		sqlDriver: "sqlserver",
+		prepareDSN: func(dsn string, development bool) string {
+			// Local SQL Server containers ship self-signed certificates
+			if development && !hasParam(dsn, "encrypt") {
+				dsn = addParam(dsn, "encrypt", "disable")
+			}
+			return dsn
+		},
+		redactDSN: func(dsn string) string {
+			if isURL(dsn) {
+				return redactURLPassword(dsn)
+			}
+			return adoPassword.ReplaceAllString(dsn, "${1}*****")
+		},
+		explain: func(err error, dsn string) error {
+			msg := strings.ToLower(err.Error())
+			if strings.Contains(msg, "login failed") || strings.Contains(msg, "login error") {
+				host := urlHost(dsn, "localhost:1433")
+				return fmt.Errorf("mssql: login failed for user. Host=%s. Check SQL Server authentication mode (enable SQL auth), ensure the user is enabled and the password is correct, and that TCP/IP is enabled. You can test with: sqlcmd -S %s -U <user> -P '<password>'", host, host)
+			}
+			return err
+		},
+	},
+	DriverPostgres: {
+		sqlDriver: "postgres",
+		prepareDSN: func(dsn string, development bool) string {
+			// lib/pq requires TLS by default; local servers usually have none
+			if development && !hasParam(dsn, "sslmode") {
+				if isURL(dsn) {
+					dsn = addParam(dsn, "sslmode", "disable")
+				} else {
+					dsn = strings.TrimSpace(dsn + " sslmode=disable")
+				}
+			}
+			return dsn
+		},
+		redactDSN: func(dsn string) string {
+			if isURL(dsn) {
+				return redactURLPassword(dsn)
+			}
+			return keywordPassword.ReplaceAllString(dsn, "${1}*****")
+		},
+		explain: func(err error, dsn string) error {
+			if strings.Contains(err.Error(), "password authentication failed") {
+				return fmt.Errorf("postgres: password authentication failed. Host=%s. Check the user and password in DATABASE_DSN: %w", urlHost(dsn, "localhost:5432"), err)
+			}
+			return err
+		},
+	},
+	DriverSQLite: {
+		sqlDriver: "sqlite",
+		prepareDSN: func(dsn string, development bool) string {
+			// Foreign keys are off by default in SQLite, and concurrent
+			// writers should wait instead of failing with SQLITE_BUSY.
+			if !strings.Contains(dsn, "foreign_keys") {
+				dsn = addParam(dsn, "_pragma", "foreign_keys(1)")
+			}
+			if !strings.Contains(dsn, "busy_timeout") {
+				dsn = addParam(dsn, "_pragma", "busy_timeout(5000)")
+			}
+			// Store times in a format SQLite date functions and ORDER BY
+			// understand instead of Go's time.String().
+			if !hasParam(dsn, "_time_format") {
+				dsn = addParam(dsn, "_time_format", "sqlite")
+			}
+			return dsn
+		},
+		redactDSN: func(dsn string) string { return dsn },
+		explain:   func(err error, dsn string) error { return err },
+		tune: func(db *sqlx.DB, dsn string) {
+			// Every connection to ":memory:" opens a new, empty database,
+			// so keep a single connection open for the life of the pool.
+			if strings.Contains(dsn, ":memory:") {
+				db.SetMaxOpenConns(1)
+				db.SetMaxIdleConns(1)
+				db.SetConnMaxLifetime(0)
+				db.SetConnMaxIdleTime(0)
+			}
+		},
+	},
+}
+
+// adoPassword matches the password of ADO (server=...;password=...) and ODBC
+// (odbc:server=...;pwd={...}) SQL Server DSNs. Values may be quoted or wrapped
+// in braces, which can hold ';'.
+var adoPassword = regexp.MustCompile(`(?i)((?:^|;|odbc:)\s*(?:password|pwd)\s*=\s*)(\{(?:[^}]|\}\})*\}|"(?:[^"]|"")*"|'(?:[^']|'')*'|[^;]*)`)
+
+// keywordPassword matches the password of key=value postgres DSNs. Values may
+// be single-quoted, with backslash escapes, to hold spaces.
+var keywordPassword = regexp.MustCompile(`(?i)(\b(?:password|pwd)\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// RedactDSN masks the credentials in a DSN for driver so it can be shown.
+func RedactDSN(driver, dsn string) string {
+	d, ok := connectors[driver]
+	if !ok {
+		return "*****"
+	}
+	return d.redactDSN(dsn)
+}
+
+// Open connects to the configured backend, retrying a few times in case the
+// server is still starting, and applies the pool settings.
+func Open(cfg config.DatabaseConfig, development bool, log *logger.Logger) (*sqlx.DB, error) {
+	d, ok := connectors[cfg.Driver]
+	if !ok {
+		return nil, fmt.Errorf("database: unsupported driver %q (want %s, %s or %s)", cfg.Driver, DriverMSSQL, DriverPostgres, DriverSQLite)
+	}
+	if !driverRegistered(d.sqlDriver) {
+		return nil, fmt.Errorf("database: %s driver is not compiled into this binary", cfg.Driver)
+	}
+
+	dsn := d.prepareDSN(cfg.DSN, development)
+	log.Info("DB DSN", "driver", cfg.Driver, "dsn", d.redactDSN(dsn))
+
+	var db *sqlx.DB
+	var err error
+	attempts := 3
+	for i := 1; i <= attempts; i++ {
+		db, err = sqlx.Connect(d.sqlDriver, dsn)
+		if err == nil {
+			break
+		}
+		log.Error("DB connection attempt failed", "attempt", i, "error", err)
+		if i < attempts {
+			time.Sleep(2 * time.Second)
+		}
+	}
+	if err != nil {
+		return nil, d.explain(err, dsn)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
+	if d.tune != nil {
+		d.tune(db, dsn)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect describes the SQL differences between backends that matter when
+// building queries: placeholder syntax, string concatenation and pagination.
+type Dialect struct {
+	Name        string
+	placeholder func(n int) string
+	concatOp    string
+	// binaryCollation orders text by code point, like Go string comparison.
+	binaryCollation string
+}
+
+var (
+	// MSSQL uses the @pN placeholders understood by go-mssqldb.
+	MSSQL = Dialect{
+		Name:            DriverMSSQL,
+		placeholder:     func(n int) string { return "@p" + strconv.Itoa(n) },
+		concatOp:        " + ",
+		binaryCollation: "Latin1_General_BIN2",
+	}
+	Postgres = Dialect{
+		Name:            DriverPostgres,
+		placeholder:     func(n int) string { return "$" + strconv.Itoa(n) },
+		concatOp:        " || ",
+		binaryCollation: `"C"`,
+	}
+	SQLite = Dialect{
+		Name:        DriverSQLite,
+		placeholder: func(int) string { return "?" },
+		concatOp:    " || ",
+	}
+)
+
+// DialectFor returns the dialect of a config.DatabaseConfig.Driver value.
+func DialectFor(driver string) (Dialect, error) {
+	switch driver {
+	case DriverMSSQL:
+		return MSSQL, nil
+	case DriverPostgres:
+		return Postgres, nil
+	case DriverSQLite:
+		return SQLite, nil
+	default:
+		return Dialect{}, fmt.Errorf("database: unsupported driver %q", driver)
+	}
+}
+
+// Placeholder returns the bind parameter for the n-th argument (1-based).
+func (d Dialect) Placeholder(n int) string {
+	return d.placeholder(n)
+}
+
+// Concat joins SQL string expressions.
+func (d Dialect) Concat(exprs ...string) string {
+	return "(" + strings.Join(exprs, d.concatOp) + ")"
+}
+
+// Binary makes a text expression compare by code point, so ordering does not
+// depend on the database's default collation. SQLite already compares text
+// that way.
+func (d Dialect) Binary(expr string) string {
+	if d.binaryCollation == "" {
+		return expr
+	}
+	return expr + " COLLATE " + d.binaryCollation
+}
+
+// EscapeLike escapes the LIKE wildcards in s (including SQL Server's "[")
+// for use with ESCAPE '\'.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `[`, `\[`)
+
+// SelectBuilder builds a SELECT with filters, ordering and pagination,
+// numbering placeholders for its dialect.
+type SelectBuilder struct {
+	dialect    Dialect
+	columns    string
+	table      string
+	conditions []string
+	args       []interface{}
+	orderBy    []string
+	paginate   bool
+	limit      int
+	offset     int
+}
+
+// Select starts a query over table returning columns.
+func (d Dialect) Select(columns, table string) *SelectBuilder {
+	return &SelectBuilder{dialect: d, columns: columns, table: table}
+}
+
+// Where adds a condition joined with AND. Each "?" in condition is bound to
+// the next value of args.
+func (b *SelectBuilder) Where(condition string, args ...interface{}) *SelectBuilder {
+	var sb strings.Builder
+	next := 0
+	for _, r := range condition {
+		if r == '?' && next < len(args) {
+			b.args = append(b.args, args[next])
+			sb.WriteString(b.dialect.Placeholder(len(b.args)))
+			next++
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	b.conditions = append(b.conditions, sb.String())
+	return b
+}
+
+// OrderBy appends ordering terms such as "created_at DESC".
+func (b *SelectBuilder) OrderBy(terms ...string) *SelectBuilder {
+	b.orderBy = append(b.orderBy, terms...)
+	return b
+}
+
+// Paginate limits the result to limit rows after skipping offset rows.
+func (b *SelectBuilder) Paginate(limit, offset int) *SelectBuilder {
+	b.paginate = true
+	b.limit = limit
+	b.offset = offset
+	return b
+}
+
+// Build returns the SQL and its arguments.
+func (b *SelectBuilder) Build() (string, []interface{}) {
+	var sb strings.Builder
+	sb.WriteString("SELECT " + b.columns + " FROM " + b.table)
+
+	if len(b.conditions) > 0 {
+		sb.WriteString(" WHERE " + strings.Join(b.conditions, " AND "))
+	}
+
+	args := append([]interface{}{}, b.args...)
+	orderBy := b.orderBy
+
+	if b.paginate && b.dialect.Name == DriverMSSQL && len(orderBy) == 0 {
+		// SQL Server only accepts OFFSET/FETCH after an ORDER BY
+		orderBy = []string{"(SELECT NULL)"}
+	}
+	if len(orderBy) > 0 {
+		sb.WriteString(" ORDER BY " + strings.Join(orderBy, ", "))
+	}
+
+	if b.paginate {
+		if b.dialect.Name == DriverMSSQL {
+			args = append(args, b.offset, b.limit)
+			sb.WriteString(" OFFSET " + b.dialect.Placeholder(len(args)-1) + " ROWS FETCH NEXT " + b.dialect.Placeholder(len(args)) + " ROWS ONLY")
+		} else {
+			args = append(args, b.limit, b.offset)
+			sb.WriteString(" LIMIT " + b.dialect.Placeholder(len(args)-1) + " OFFSET " + b.dialect.Placeholder(len(args)))
+		}
+	}
+
+	return sb.String(), args
+}
@@ -6,14 +6,16 @@ import (
 )
 
 var (
-	ErrNotFound          = errors.New("resource not found")
-	ErrInvalidInput      = errors.New("invalid input")
-	ErrUnauthorized      = errors.New("unauthorized")
-	ErrForbidden         = errors.New("forbidden")
-	ErrInternalServer    = errors.New("internal server error")
-	ErrConflict          = errors.New("resource already exists")
-	ErrBadRequest        = errors.New("bad request")
-	ErrValidation        = errors.New("validation error")
+	ErrNotFound            = errors.New("resource not found")
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrInternalServer      = errors.New("internal server error")
+	ErrConflict            = errors.New("resource already exists")
+	ErrBadRequest          = errors.New("bad request")
+	ErrValidation          = errors.New("validation error")
+	ErrConcurrencyConflict = errors.New("resource was modified concurrently")
+	ErrPreconditionFailed  = errors.New("precondition failed")
 )
 
 type AppError struct {
@@ -68,6 +70,14 @@ func NewUnauthorizedError(message string) *AppError {
 	}
 }
 
+func NewForbiddenError(message string) *AppError {
+	return &AppError{
+		Code:    403,
+		Message: message,
+		Err:     ErrForbidden,
+	}
+}
+
 func NewInternalError(message string, err error) *AppError {
 	return &AppError{
 		Code:    500,
@@ -75,3 +85,19 @@ func NewInternalError(message string, err error) *AppError {
 		Err:     err,
 	}
 }
+
+func NewConflictError(message string) *AppError {
+	return &AppError{
+		Code:    409,
+		Message: message,
+		Err:     ErrConcurrencyConflict,
+	}
+}
+
+func NewPreconditionFailedError(message string) *AppError {
+	return &AppError{
+		Code:    412,
+		Message: message,
+		Err:     ErrPreconditionFailed,
+	}
+}
@@ -0,0 +1,18 @@
+package http
+
+import (
+	"go-architecture/internal/shared/auth"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JWKSHandler publishes the public token verification keys so that other
+// services can verify access tokens without sharing a secret.
+func JWKSHandler(keys *auth.KeySet) fiber.Handler {
+	jwks := keys.JWKS()
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return c.JSON(jwks)
+	}
+}
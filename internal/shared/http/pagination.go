@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Page describes the page of a list response for pagination links.
+type Page struct {
+	Total   int
+	Limit   int
+	Offset  int
+	HasMore bool
+	// Cursor is set when the page was requested with a cursor; NextCursor
+	// continues after it.
+	Cursor     string
+	NextCursor string
+}
+
+// SetPaginationLinks sets an RFC 8288 Link header with the first, last,
+// previous and next pages. Links reuse the request's query string, so filters
+// are preserved. Cursor pages link forward with next_cursor and have no
+// "prev", since a cursor only points forward.
+func SetPaginationLinks(c *fiber.Ctx, page Page) {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return
+	}
+	query.Set("limit", strconv.Itoa(page.Limit))
+
+	base := c.BaseURL() + c.Path()
+	link := func(rel string, set func(q url.Values)) string {
+		q := url.Values{}
+		for key, values := range query {
+			q[key] = values
+		}
+		q.Del("cursor")
+		q.Del("offset")
+		set(q)
+		return "<" + base + "?" + q.Encode() + `>; rel="` + rel + `"`
+	}
+	atOffset := func(offset int) func(q url.Values) {
+		return func(q url.Values) {
+			if offset > 0 {
+				q.Set("offset", strconv.Itoa(offset))
+			}
+		}
+	}
+
+	var links []string
+	links = append(links, link("first", atOffset(0)))
+
+	lastOffset := 0
+	if page.Total > 0 && page.Limit > 0 {
+		lastOffset = (page.Total - 1) / page.Limit * page.Limit
+	}
+	links = append(links, link("last", atOffset(lastOffset)))
+
+	if page.Cursor == "" && page.Offset > 0 {
+		prev := page.Offset - page.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, link("prev", atOffset(prev)))
+	}
+
+	if page.HasMore {
+		if page.Cursor != "" && page.NextCursor != "" {
+			links = append(links, link("next", func(q url.Values) { q.Set("cursor", page.NextCursor) }))
+		} else {
+			links = append(links, link("next", atOffset(page.Offset+page.Limit)))
+		}
+	}
+
+	c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+}
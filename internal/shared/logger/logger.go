@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"log/slog"
 	"os"
 )
@@ -10,7 +11,13 @@ type Logger struct {
 }
 
 func NewLogger() *Logger {
-	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	return New(os.Stdout)
+}
+
+// New returns a logger writing JSON lines to w. Commands whose output goes
+// to stdout log to stderr instead.
+func New(w io.Writer) *Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	})
 
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"go-architecture/internal/shared/auth"
+	"go-architecture/internal/shared/errors"
+)
+
+// HeaderAPIKey carries the raw API key of machine clients.
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyAuthenticator resolves a raw API key to the principal it acts as.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+// APIKeyProtected authenticates requests with the X-API-Key header and sets
+// the same principal as JWTProtected.
+func APIKeyProtected(authenticator APIKeyAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderAPIKey)
+		if key == "" {
+			return errors.NewUnauthorizedError("Missing API key")
+		}
+
+		principal, err := authenticator.Authenticate(c.UserContext(), key)
+		if err != nil {
+			return err
+		}
+
+		SetPrincipal(c, principal)
+
+		return c.Next()
+	}
+}
+
+// JWTOrAPIKey uses apiKeyAuth when the request sends an X-API-Key header
+// and jwtAuth otherwise.
+func JWTOrAPIKey(jwtAuth, apiKeyAuth fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get(HeaderAPIKey) != "" {
+			return apiKeyAuth(c)
+		}
+		return jwtAuth(c)
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Authorizer decides whether the principal in ctx may perform action on
+// resource, returning an AppError when it may not.
+type Authorizer interface {
+	Authorize(ctx context.Context, action, resource string) error
+}
+
+// RequirePermission rejects requests whose principal lacks the
+// "resource:action" permission. It must run after an authentication
+// middleware.
+func RequirePermission(authorizer Authorizer, resource, action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := authorizer.Authorize(c.UserContext(), action, resource); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
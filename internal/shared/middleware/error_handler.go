@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	stderrors "errors"
+
 	"github.com/gofiber/fiber/v2"
 	"go-architecture/internal/shared/errors"
 )
@@ -12,13 +14,21 @@ func ErrorHandler(c *fiber.Ctx, err error) error {
 	var details map[string]interface{}
 
 	// Check if it's an AppError
-	if appErr, ok := err.(*errors.AppError); ok {
+	var appErr *errors.AppError
+	var fiberErr *fiber.Error
+	if stderrors.As(err, &appErr) {
 		code = appErr.Code
 		message = appErr.Message
 		details = appErr.Details
-	} else if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
-		message = e.Message
+	} else if stderrors.As(err, &fiberErr) {
+		code = fiberErr.Code
+		message = fiberErr.Message
+	} else if stderrors.Is(err, errors.ErrPreconditionFailed) {
+		code = fiber.StatusPreconditionFailed
+		message = "Precondition Failed"
+	} else if stderrors.Is(err, errors.ErrConcurrencyConflict) {
+		code = fiber.StatusConflict
+		message = "Resource was modified concurrently"
 	}
 
 	return c.Status(code).JSON(fiber.Map{
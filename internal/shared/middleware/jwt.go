@@ -1,21 +1,26 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
+	"go-architecture/internal/shared/auth"
 	"go-architecture/internal/shared/errors"
 )
 
-type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	jwt.RegisteredClaims
+// principalLocal is the fiber.Ctx Locals key holding the *auth.Principal.
+const principalLocal = "principal"
+
+// RevocationChecker reports whether an access token ID (jti) was revoked
+// before its expiry, e.g. by logout.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
 }
 
-func JWTProtected(secret string) fiber.Handler {
+// JWTProtected verifies the bearer token and rejects revoked tokens. The
+// revocation check is skipped when revocations is nil.
+func JWTProtected(verifier *auth.Verifier, revocations RevocationChecker) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -29,41 +34,42 @@ func JWTProtected(secret string) fiber.Handler {
 
 		tokenString := parts[1]
 
-		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims := &auth.Claims{}
+		token, err := verifier.Parse(tokenString, claims)
+		if err != nil {
+			appErr := errors.NewUnauthorizedError("Invalid or expired token")
+			appErr.Details = map[string]interface{}{"cause": auth.ErrorCause(err)}
+			return appErr
+		}
+		if !token.Valid {
 			return errors.NewUnauthorizedError("Invalid or expired token")
 		}
 
-		claims, ok := token.Claims.(*Claims)
-		if !ok {
-			return errors.NewUnauthorizedError("Invalid token claims")
+		if revocations != nil && claims.ID != "" {
+			revoked, err := revocations.IsRevoked(c.Context(), claims.ID)
+			if err != nil {
+				return errors.NewInternalError("Failed to check token revocation", err)
+			}
+			if revoked {
+				return errors.NewUnauthorizedError("Token has been revoked")
+			}
 		}
 
-		// Store user info in context
-		c.Locals("user_id", claims.UserID)
-		c.Locals("email", claims.Email)
-		c.Locals("role", claims.Role)
+		SetPrincipal(c, claims.Principal())
 
 		return c.Next()
 	}
 }
 
-func RequireRole(roles ...string) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		userRole, ok := c.Locals("role").(string)
-		if !ok {
-			return errors.NewUnauthorizedError("User role not found")
-		}
-
-		for _, role := range roles {
-			if userRole == role {
-				return c.Next()
-			}
-		}
+// SetPrincipal stores the authenticated principal both in the fiber context
+// and in the request's context.Context (c.UserContext()).
+func SetPrincipal(c *fiber.Ctx, principal *auth.Principal) {
+	c.Locals(principalLocal, principal)
+	c.SetUserContext(auth.WithPrincipal(c.UserContext(), principal))
+}
 
-		return errors.NewAppError(403, "Insufficient permissions", errors.ErrForbidden)
-	}
+// Principal returns the principal set by an authentication middleware.
+func Principal(c *fiber.Ctx) (*auth.Principal, bool) {
+	principal, ok := c.Locals(principalLocal).(*auth.Principal)
+	return principal, ok && principal != nil
 }
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go-architecture/internal/shared/auth"
+)
+
+type revokedIDs map[string]bool
+
+func (r revokedIDs) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return r[tokenID], nil
+}
+
+func TestJWTProtected(t *testing.T) {
+	keys := auth.NewHMACKeySet("secret")
+	issuer := auth.NewTokenIssuer(keys, auth.IssuerOptions{TTL: time.Minute})
+	issue := func() *auth.IssuedToken {
+		issued, err := issuer.Issue(auth.Principal{UserID: "user-1"})
+		if err != nil {
+			t.Fatalf("Issue: %v", err)
+		}
+		return issued
+	}
+
+	live, revoked := issue(), issue()
+	revocations := revokedIDs{revoked.ID: true}
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/", JWTProtected(auth.NewVerifier(keys, auth.VerifierOptions{}), revocations), func(c *fiber.Ctx) error {
+		principal, _ := Principal(c)
+		return c.SendString(principal.TokenID)
+	})
+
+	cases := []struct {
+		name          string
+		authorization string
+		code          int
+		message       string
+	}{
+		{"live token", "Bearer " + live.Token, 200, ""},
+		{"revoked jti", "Bearer " + revoked.Token, 401, "Token has been revoked"},
+		{"missing header", "", 401, "Missing authorization header"},
+		{"not a bearer token", "Basic " + live.Token, 401, "Invalid authorization header format"},
+		{"invalid token", "Bearer " + live.Token + "x", 401, "Invalid or expired token"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if c.authorization != "" {
+				req.Header.Set("Authorization", c.authorization)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Test: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != c.code {
+				t.Fatalf("status %d, want %d", resp.StatusCode, c.code)
+			}
+			if c.code != 200 {
+				var body struct {
+					Error struct {
+						Message string `json:"message"`
+					} `json:"error"`
+				}
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatalf("Decode: %v", err)
+				}
+				if body.Error.Message != c.message {
+					t.Fatalf("message %q, want %q", body.Error.Message, c.message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestJSONPatch runs the examples of RFC 6902, Appendix A, followed by the
+// cases they leave out.
+func TestJSONPatch(t *testing.T) {
+	cases := []struct {
+		name       string
+		doc, patch string
+		want       string
+		err        error
+	}{
+		// RFC 6902, Appendix A
+		{"A.1 adding an object member",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			`{"baz": "qux", "foo": "bar"}`, nil},
+		{"A.2 adding an array element",
+			`{"foo": ["bar", "baz"]}`,
+			`[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			`{"foo": ["bar", "qux", "baz"]}`, nil},
+		{"A.3 removing an object member",
+			`{"baz": "qux", "foo": "bar"}`,
+			`[{"op": "remove", "path": "/baz"}]`,
+			`{"foo": "bar"}`, nil},
+		{"A.4 removing an array element",
+			`{"foo": ["bar", "qux", "baz"]}`,
+			`[{"op": "remove", "path": "/foo/1"}]`,
+			`{"foo": ["bar", "baz"]}`, nil},
+		{"A.5 replacing a value",
+			`{"baz": "qux", "foo": "bar"}`,
+			`[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			`{"baz": "boo", "foo": "bar"}`, nil},
+		{"A.6 moving a value",
+			`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			`[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			`{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`, nil},
+		{"A.7 moving an array element",
+			`{"foo": ["all", "grass", "cows", "eat"]}`,
+			`[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			`{"foo": ["all", "cows", "eat", "grass"]}`, nil},
+		{"A.8 testing a value: success",
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			`[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`, nil},
+		{"A.9 testing a value: error",
+			`{"baz": "qux"}`,
+			`[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			"", ErrTestFailed},
+		{"A.10 adding a nested member object",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			`{"foo": "bar", "child": {"grandchild": {}}}`, nil},
+		{"A.11 ignoring unrecognized elements",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			`{"foo": "bar", "baz": "qux"}`, nil},
+		{"A.12 adding to a nonexistent target",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			"", ErrPathNotFound},
+		{"A.13 invalid JSON Patch document",
+			`{"baz": "qux", "foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux", "op": "remove"}]`,
+			"", ErrMalformed},
+		{"A.14 ~ escape ordering",
+			`{"/": 9, "~1": 10}`,
+			`[{"op": "test", "path": "/~01", "value": 10}]`,
+			`{"/": 9, "~1": 10}`, nil},
+		{"A.15 comparing strings and numbers",
+			`{"/": 9, "~1": 10}`,
+			`[{"op": "test", "path": "/~01", "value": "10"}]`,
+			"", ErrTestFailed},
+		{"A.16 adding an array value",
+			`{"foo": ["bar"]}`,
+			`[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			`{"foo": ["bar", ["abc", "def"]]}`, nil},
+
+		// Pointers
+		{"escaped slash and tilde",
+			`{}`,
+			`[{"op": "add", "path": "/a~1b", "value": 1}, {"op": "add", "path": "/m~0n", "value": 2}]`,
+			`{"a/b": 1, "m~n": 2}`, nil},
+		{"empty member name",
+			`{"": 1}`,
+			`[{"op": "replace", "path": "/", "value": 2}]`,
+			`{"": 2}`, nil},
+		{"replace the root",
+			`{"foo": "bar"}`,
+			`[{"op": "replace", "path": "", "value": [1]}]`,
+			`[1]`, nil},
+		{"pointer without leading slash",
+			`{"foo": "bar"}`,
+			`[{"op": "remove", "path": "foo"}]`,
+			"", ErrMalformed},
+
+		// Array indexes
+		{"add at the end by index",
+			`{"foo": ["bar"]}`,
+			`[{"op": "add", "path": "/foo/1", "value": "baz"}]`,
+			`{"foo": ["bar", "baz"]}`, nil},
+		{"add past the end",
+			`{"foo": ["bar"]}`,
+			`[{"op": "add", "path": "/foo/2", "value": "baz"}]`,
+			"", ErrPathNotFound},
+		{"index with a leading zero",
+			`{"foo": ["bar", "baz"]}`,
+			`[{"op": "remove", "path": "/foo/01"}]`,
+			"", ErrPathNotFound},
+		{"negative index",
+			`{"foo": ["bar"]}`,
+			`[{"op": "remove", "path": "/foo/-1"}]`,
+			"", ErrPathNotFound},
+		{"remove the end marker",
+			`{"foo": ["bar"]}`,
+			`[{"op": "remove", "path": "/foo/-"}]`,
+			"", ErrPathNotFound},
+		{"nested array insertion",
+			`{"foo": [["a", "c"]]}`,
+			`[{"op": "add", "path": "/foo/0/1", "value": "b"}]`,
+			`{"foo": [["a", "b", "c"]]}`, nil},
+
+		// remove, replace, move and copy
+		{"remove a missing member",
+			`{"foo": "bar"}`,
+			`[{"op": "remove", "path": "/baz"}]`,
+			"", ErrPathNotFound},
+		{"replace a missing member",
+			`{"foo": "bar"}`,
+			`[{"op": "replace", "path": "/baz", "value": 1}]`,
+			"", ErrPathNotFound},
+		{"move into its own child",
+			`{"a": {"b": {}}}`,
+			`[{"op": "move", "from": "/a", "path": "/a/b/c"}]`,
+			"", ErrMalformed},
+		{"move onto itself",
+			`{"a": {"b": 1}}`,
+			`[{"op": "move", "from": "/a", "path": "/a"}]`,
+			`{"a": {"b": 1}}`, nil},
+		{"move to a sibling sharing a prefix",
+			`{"a": 1}`,
+			`[{"op": "move", "from": "/a", "path": "/ab"}]`,
+			`{"ab": 1}`, nil},
+		{"copy is independent of its source",
+			`{"a": {"b": 1}}`,
+			`[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "replace", "path": "/c/b", "value": 2}]`,
+			`{"a": {"b": 1}, "c": {"b": 2}}`, nil},
+		{"copy a missing value",
+			`{"a": 1}`,
+			`[{"op": "copy", "from": "/b", "path": "/c"}]`,
+			"", ErrPathNotFound},
+
+		// test
+		{"test numbers by value",
+			`{"n": 1.0}`,
+			`[{"op": "test", "path": "/n", "value": 1}]`,
+			`{"n": 1.0}`, nil},
+		{"test objects regardless of member order",
+			`{"o": {"a": 1, "b": [true, null]}}`,
+			`[{"op": "test", "path": "/o", "value": {"b": [true, null], "a": 1}}]`,
+			`{"o": {"a": 1, "b": [true, null]}}`, nil},
+		{"test a missing member",
+			`{}`,
+			`[{"op": "test", "path": "/a", "value": null}]`,
+			"", ErrPathNotFound},
+		{"failed test discards earlier operations",
+			`{"a": 1}`,
+			`[{"op": "replace", "path": "/a", "value": 2}, {"op": "test", "path": "/a", "value": 1}]`,
+			"", ErrTestFailed},
+
+		// Malformed patches
+		{"not an array", `{}`, `{"op": "add"}`, "", ErrMalformed},
+		{"unknown op", `{}`, `[{"op": "merge", "path": "/a"}]`, "", ErrMalformed},
+		{"missing path", `{}`, `[{"op": "add", "value": 1}]`, "", ErrMalformed},
+		{"missing value", `{}`, `[{"op": "add", "path": "/a"}]`, "", ErrMalformed},
+		{"missing from", `{"a": 1}`, `[{"op": "move", "path": "/b"}]`, "", ErrMalformed},
+		{"null value is a value",
+			`{}`,
+			`[{"op": "add", "path": "/a", "value": null}]`,
+			`{"a": null}`, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(c.doc), []byte(c.patch))
+			assertResult(t, got, err, c.want, c.err)
+		})
+	}
+}
+
+func TestJSONPatchOperationError(t *testing.T) {
+	_, err := JSONPatch([]byte(`{"a": 1}`), []byte(`[{"op": "test", "path": "/a", "value": 1}, {"op": "remove", "path": "/b"}]`))
+
+	var opErr *OperationError
+	if !errors.As(err, &opErr) {
+		t.Fatalf("got %v, want an *OperationError", err)
+	}
+	if opErr.Index != 1 || opErr.Op != "remove" || opErr.Path != "/b" {
+		t.Errorf("OperationError = %+v, want operation 1 (remove /b)", opErr)
+	}
+}
+
+// TestMergePatch runs the examples of RFC 7386, Appendix A.
+func TestMergePatch(t *testing.T) {
+	cases := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, c := range cases {
+		got, err := MergePatch([]byte(c.doc), []byte(c.patch))
+		if err != nil {
+			t.Errorf("MergePatch(%s, %s): %v", c.doc, c.patch, err)
+			continue
+		}
+		assertJSON(t, got, c.want)
+	}
+
+	if _, err := MergePatch([]byte(`{}`), []byte(`{"a":`)); !errors.Is(err, ErrMalformed) {
+		t.Errorf("MergePatch of invalid JSON: got %v, want ErrMalformed", err)
+	}
+}
+
+func assertResult(t *testing.T, got []byte, err error, want string, wantErr error) {
+	t.Helper()
+
+	if wantErr != nil {
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("got %s, %v; want %v", got, err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("got %v, want %s", err, want)
+	}
+	assertJSON(t, got, want)
+}
+
+// assertJSON compares JSON documents by value.
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	g, err := decode(got)
+	if err != nil {
+		t.Fatalf("result %s is not JSON: %v", got, err)
+	}
+	w, err := decode([]byte(want))
+	if err != nil {
+		t.Fatalf("expected %s is not JSON: %v", want, err)
+	}
+	if !equal(g, w) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
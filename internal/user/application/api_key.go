@@ -0,0 +1,182 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-architecture/internal/shared/auth"
+	"go-architecture/internal/shared/authz"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/user/domain"
+)
+
+// Authorizer checks that the principal in ctx may perform action on
+// resource. It is satisfied by *authz.Policy.
+type Authorizer interface {
+	Authorize(ctx context.Context, action, resource string) error
+}
+
+// APIKeyService manages API keys and authenticates requests made with them.
+type APIKeyService struct {
+	repo       domain.APIKeyRepository
+	users      domain.UserRepository
+	authorizer Authorizer
+	validator  *Validator
+}
+
+func NewAPIKeyService(repo domain.APIKeyRepository, users domain.UserRepository, authorizer Authorizer) *APIKeyService {
+	return &APIKeyService{
+		repo:       repo,
+		users:      users,
+		authorizer: authorizer,
+		validator:  NewValidator(),
+	}
+}
+
+// Create issues a key for dto.UserID, or for the caller when it is empty.
+// A key acts as its owner, so issuing one for another user takes the
+// user:write permission, which could reset their password anyway. The raw
+// key is only ever returned here.
+func (s *APIKeyService) Create(ctx context.Context, dto CreateAPIKeyDTO) (*CreatedAPIKeyDTO, error) {
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+
+	actor := auth.ActorID(ctx)
+	userID := dto.UserID
+	if userID == "" {
+		userID = actor
+	}
+	if userID != actor {
+		if err := s.authorizer.Authorize(ctx, authz.ActionWrite, authz.ResourceUser); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.NewValidationError("User not found", nil)
+		}
+		return nil, apperrors.NewInternalError("Failed to get user", err)
+	}
+
+	key, raw, err := domain.NewAPIKey(user.ID, dto.Name, dto.Scopes, dto.ExpiresAt, actor)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAPIKeyName) || errors.Is(err, domain.ErrInvalidAPIKeyScope) || errors.Is(err, domain.ErrAPIKeyExpiry) {
+			return nil, apperrors.NewValidationError(err.Error(), nil)
+		}
+		return nil, apperrors.NewInternalError("Failed to generate API key", err)
+	}
+
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, apperrors.NewInternalError("Failed to create API key", err)
+	}
+
+	return &CreatedAPIKeyDTO{
+		APIKeyResponseDTO: ToAPIKeyResponseDTO(key),
+		Key:               raw,
+	}, nil
+}
+
+func (s *APIKeyService) GetAll(ctx context.Context, filtersDTO APIKeyListFiltersDTO) ([]APIKeyResponseDTO, error) {
+	if err := s.validator.Validate(filtersDTO); err != nil {
+		return nil, err
+	}
+
+	if filtersDTO.Limit == 0 {
+		filtersDTO.Limit = 20
+	}
+
+	keys, err := s.repo.FindAll(ctx, domain.APIKeyFilters{
+		UserID: filtersDTO.UserID,
+		Limit:  filtersDTO.Limit,
+		Offset: filtersDTO.Offset,
+	})
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to get API keys", err)
+	}
+
+	return ToAPIKeyResponseDTOList(keys), nil
+}
+
+// SetExpiry replaces the expiry of an active key.
+func (s *APIKeyService) SetExpiry(ctx context.Context, id string, dto UpdateAPIKeyDTO) (*APIKeyResponseDTO, error) {
+	key, err := s.findKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.RevokedAt != nil {
+		return nil, apperrors.NewValidationError("API key has been revoked", nil)
+	}
+
+	if err := key.SetExpiry(dto.ExpiresAt); err != nil {
+		return nil, apperrors.NewValidationError(err.Error(), nil)
+	}
+
+	if err := s.repo.Update(ctx, key); err != nil {
+		return nil, apperrors.NewInternalError("Failed to update API key", err)
+	}
+
+	response := ToAPIKeyResponseDTO(key)
+	return &response, nil
+}
+
+// Revoke disables a key permanently. Revoking a revoked key is a no-op.
+func (s *APIKeyService) Revoke(ctx context.Context, id string) error {
+	key, err := s.findKey(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	key.Revoke(time.Now().UTC())
+
+	if err := s.repo.Update(ctx, key); err != nil {
+		return apperrors.NewInternalError("Failed to revoke API key", err)
+	}
+
+	return nil
+}
+
+// Authenticate resolves a raw key to the principal of its owner, restricted
+// to the key's scopes. Unknown, expired and revoked keys, as well as keys of
+// inactive users, all yield the same unauthorized error.
+func (s *APIKeyService) Authenticate(ctx context.Context, raw string) (*auth.Principal, error) {
+	key, err := s.repo.FindByHash(ctx, domain.HashAPIKey(raw))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.NewUnauthorizedError(domain.ErrAPIKeyInvalid.Error())
+		}
+		return nil, apperrors.NewInternalError("Failed to get API key", err)
+	}
+
+	if !key.IsActive(time.Now().UTC()) {
+		return nil, apperrors.NewUnauthorizedError(domain.ErrAPIKeyInvalid.Error())
+	}
+
+	user, err := s.users.FindByID(ctx, key.UserID)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, apperrors.NewInternalError("Failed to get user", err)
+	}
+	if user == nil || !user.Active {
+		return nil, apperrors.NewUnauthorizedError(domain.ErrAPIKeyInvalid.Error())
+	}
+
+	principal := principalOf(user)
+	principal.Scopes = key.Scopes
+	principal.APIKeyID = key.ID
+	return &principal, nil
+}
+
+func (s *APIKeyService) findKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.NewNotFoundError("API key not found")
+		}
+		return nil, apperrors.NewInternalError("Failed to get API key", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,105 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+
+	"go-architecture/internal/shared/auth"
+	"go-architecture/internal/shared/authz"
+	"go-architecture/internal/shared/config"
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/shared/logger"
+	"go-architecture/internal/user/application"
+	"go-architecture/internal/user/domain"
+	"go-architecture/internal/user/infra/sqlite"
+	"go-architecture/migrations"
+)
+
+// openDB returns a fresh, migrated in-memory database.
+func openDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := database.Open(config.DatabaseConfig{Driver: database.DriverSQLite, DSN: ":memory:"}, true, logger.NewLogger())
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := database.Migrate(context.Background(), db, database.DriverSQLite, migrations.FS); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return db
+}
+
+func createUser(t *testing.T, users domain.UserRepository, username string, roles ...string) *domain.User {
+	t.Helper()
+
+	user, err := domain.NewUser(username, username+"@example.com", "hash", roles)
+	if err != nil {
+		t.Fatalf("NewUser: %v", err)
+	}
+	if err := users.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return user
+}
+
+func TestCreateAPIKeyForAnotherUser(t *testing.T) {
+	db := openDB(t)
+	users := sqlite.NewUserRepository(db)
+	policy := authz.NewPolicy(map[string][]string{
+		domain.RoleAdmin: {authz.Wildcard},
+		// Users may manage their own keys, but not other users.
+		domain.RoleUser: {"product:read", "api_key:*"},
+	})
+	service := application.NewAPIKeyService(sqlite.NewAPIKeyRepository(db), users, policy)
+
+	admin := createUser(t, users, "admin", domain.RoleAdmin)
+	alice := createUser(t, users, "alice", domain.RoleUser)
+	bob := createUser(t, users, "bob", domain.RoleUser)
+
+	as := func(user *domain.User) context.Context {
+		return auth.WithPrincipal(context.Background(), &auth.Principal{UserID: user.ID, Roles: user.Roles})
+	}
+	create := func(ctx context.Context, userID string) (*application.CreatedAPIKeyDTO, error) {
+		return service.Create(ctx, application.CreateAPIKeyDTO{Name: "etl", UserID: userID, Scopes: []string{"product:read"}})
+	}
+
+	cases := []struct {
+		name   string
+		caller *domain.User
+		userID string
+		owner  *domain.User
+	}{
+		{"own key by default", alice, "", alice},
+		{"own key by ID", alice, alice.ID, alice},
+		{"another user's key with user:write", admin, bob.ID, bob},
+		{"another user's key without user:write", alice, bob.ID, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			created, err := create(as(c.caller), c.userID)
+			if c.owner == nil {
+				var appErr *apperrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != 403 {
+					t.Fatalf("got %v, want a 403", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if created.UserID != c.owner.ID || created.CreatedBy != c.caller.ID {
+				t.Fatalf("key of %s created by %s, want %s by %s", created.UserID, created.CreatedBy, c.owner.ID, c.caller.ID)
+			}
+		})
+	}
+}
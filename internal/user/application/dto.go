@@ -0,0 +1,104 @@
+package application
+
+import "time"
+
+type RegisterUserDTO struct {
+	Username string `json:"username" validate:"required,min=3,max=50"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type CreateUserDTO struct {
+	Username string   `json:"username" validate:"required,min=3,max=50"`
+	Email    string   `json:"email" validate:"required,email,max=255"`
+	Password string   `json:"password" validate:"required,min=8,max=72"`
+	Roles    []string `json:"roles" validate:"required,min=1,dive,oneof=admin user"`
+}
+
+type UpdateUserDTO struct {
+	Email    *string  `json:"email" validate:"omitempty,email,max=255"`
+	Roles    []string `json:"roles" validate:"omitempty,min=1,dive,oneof=admin user"`
+	Active   *bool    `json:"active"`
+	Password *string  `json:"password" validate:"omitempty,min=8,max=72"`
+}
+
+type ChangePasswordDTO struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"`
+}
+
+type UserResponseDTO struct {
+	ID        string   `json:"id"`
+	Username  string   `json:"username"`
+	Email     string   `json:"email"`
+	Roles     []string `json:"roles"`
+	Active    bool     `json:"active"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type UserListFiltersDTO struct {
+	Role   string `query:"role"`
+	Active *bool  `query:"active"`
+	Limit  int    `query:"limit" validate:"max=100"`
+	Offset int    `query:"offset" validate:"gte=0"`
+}
+
+type LoginDTO struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutDTO struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type CreateAPIKeyDTO struct {
+	Name string `json:"name" validate:"required,min=3,max=100"`
+	// UserID is the user the key acts for; it defaults to the caller.
+	UserID    string     `json:"user_id" validate:"omitempty,uuid"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// UpdateAPIKeyDTO replaces the expiry of a key; a null expires_at means the
+// key never expires.
+type UpdateAPIKeyDTO struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyResponseDTO struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt string     `json:"created_at"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// CreatedAPIKeyDTO is returned once, on creation, with the raw key.
+type CreatedAPIKeyDTO struct {
+	APIKeyResponseDTO
+	Key string `json:"key"`
+}
+
+type APIKeyListFiltersDTO struct {
+	UserID string `query:"user_id"`
+	Limit  int    `query:"limit" validate:"max=100"`
+	Offset int    `query:"offset" validate:"gte=0"`
+}
+
+type TokenPairDTO struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
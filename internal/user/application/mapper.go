@@ -0,0 +1,47 @@
+package application
+
+import (
+	"go-architecture/internal/user/domain"
+)
+
+func ToUserResponseDTO(user *domain.User) UserResponseDTO {
+	return UserResponseDTO{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Roles:     user.Roles,
+		Active:    user.Active,
+		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func ToUserResponseDTOList(users []*domain.User) []UserResponseDTO {
+	dtos := make([]UserResponseDTO, len(users))
+	for i, user := range users {
+		dtos[i] = ToUserResponseDTO(user)
+	}
+	return dtos
+}
+
+func ToAPIKeyResponseDTO(key *domain.APIKey) APIKeyResponseDTO {
+	return APIKeyResponseDTO{
+		ID:        key.ID,
+		UserID:    key.UserID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		ExpiresAt: key.ExpiresAt,
+		CreatedAt: key.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedBy: key.CreatedBy,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+func ToAPIKeyResponseDTOList(keys []*domain.APIKey) []APIKeyResponseDTO {
+	dtos := make([]APIKeyResponseDTO, len(keys))
+	for i, key := range keys {
+		dtos[i] = ToAPIKeyResponseDTO(key)
+	}
+	return dtos
+}
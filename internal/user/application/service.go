@@ -0,0 +1,356 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-architecture/internal/shared/auth"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/user/domain"
+)
+
+type UserService struct {
+	repo      domain.UserRepository
+	uow       UnitOfWork
+	hasher    domain.PasswordHasher
+	validator *Validator
+	// accessTTL is how long access tokens live, so how far back signing the
+	// user out has to look for ones still valid.
+	accessTTL time.Duration
+
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+// NewUserService returns a service that signs users out, revoking access
+// tokens issued within accessTTL, when their password or roles change or
+// their account is deactivated or deleted.
+func NewUserService(repo domain.UserRepository, uow UnitOfWork, hasher domain.PasswordHasher, accessTTL time.Duration) *UserService {
+	return &UserService{
+		repo:      repo,
+		uow:       uow,
+		hasher:    hasher,
+		validator: NewValidator(),
+		accessTTL: accessTTL,
+	}
+}
+
+// Register creates a self-service account with the "user" role.
+func (s *UserService) Register(ctx context.Context, dto RegisterUserDTO) (*UserResponseDTO, error) {
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+
+	return s.create(ctx, dto.Username, dto.Email, dto.Password, []string{domain.RoleUser})
+}
+
+// Create lets an administrator create a user with any roles.
+func (s *UserService) Create(ctx context.Context, dto CreateUserDTO) (*UserResponseDTO, error) {
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+
+	return s.create(ctx, dto.Username, dto.Email, dto.Password, dto.Roles)
+}
+
+// EnsureAdmin creates an administrator account if no user with that
+// username exists yet. It is used to bootstrap a fresh installation.
+func (s *UserService) EnsureAdmin(ctx context.Context, username, email, password string) error {
+	exists, err := s.repo.ExistsByUsername(ctx, username)
+	if err != nil {
+		return apperrors.NewInternalError("Failed to check user existence", err)
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = s.create(ctx, username, email, password, []string{domain.RoleAdmin})
+	return err
+}
+
+func (s *UserService) create(ctx context.Context, username, email, password string, roles []string) (*UserResponseDTO, error) {
+	if err := domain.ValidatePassword(password); err != nil {
+		return nil, apperrors.NewValidationError(err.Error(), nil)
+	}
+
+	exists, err := s.repo.ExistsByUsername(ctx, username)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to check user existence", err)
+	}
+	if exists {
+		return nil, apperrors.NewAppError(409, "User with this username already exists", apperrors.ErrConflict)
+	}
+
+	exists, err = s.repo.ExistsByEmail(ctx, email)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to check user existence", err)
+	}
+	if exists {
+		return nil, apperrors.NewAppError(409, "User with this email already exists", apperrors.ErrConflict)
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to hash password", err)
+	}
+
+	user, err := domain.NewUser(username, email, hash, roles)
+	if err != nil {
+		return nil, apperrors.NewValidationError(err.Error(), nil)
+	}
+
+	if err := s.repo.Create(ctx, user); err != nil {
+		return nil, apperrors.NewInternalError("Failed to create user", err)
+	}
+
+	response := ToUserResponseDTO(user)
+	return &response, nil
+}
+
+// VerifyCredentials checks a username and password and returns the principal
+// to put in an access token. Unknown users, wrong passwords and inactive
+// accounts all yield the same unauthorized error.
+func (s *UserService) VerifyCredentials(ctx context.Context, username, password string) (*auth.Principal, error) {
+	user, err := s.repo.FindByUsername(ctx, username)
+	if err != nil {
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.NewInternalError("Failed to get user", err)
+		}
+		// Spend the same time as a real comparison so response times do not
+		// reveal which usernames exist.
+		_ = s.hasher.Compare(s.dummyPasswordHash(), password)
+		return nil, apperrors.NewUnauthorizedError(domain.ErrInvalidCredentials.Error())
+	}
+
+	if err := s.hasher.Compare(user.PasswordHash, password); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			return nil, apperrors.NewUnauthorizedError(domain.ErrInvalidCredentials.Error())
+		}
+		return nil, apperrors.NewInternalError("Failed to verify password", err)
+	}
+
+	if !user.Active {
+		return nil, apperrors.NewUnauthorizedError(domain.ErrInvalidCredentials.Error())
+	}
+
+	principal := principalOf(user)
+	return &principal, nil
+}
+
+// ChangePassword replaces the user's password and signs them out everywhere,
+// including the session the change was made from.
+func (s *UserService) ChangePassword(ctx context.Context, userID string, dto ChangePasswordDTO) error {
+	if err := s.validator.Validate(dto); err != nil {
+		return err
+	}
+
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.hasher.Compare(user.PasswordHash, dto.CurrentPassword); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			return apperrors.NewValidationError("Current password is incorrect", nil)
+		}
+		return apperrors.NewInternalError("Failed to verify password", err)
+	}
+
+	if err := s.setPassword(user, dto.NewPassword); err != nil {
+		return err
+	}
+
+	return s.save(ctx, user, true)
+}
+
+func (s *UserService) GetByID(ctx context.Context, id string) (*UserResponseDTO, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToUserResponseDTO(user)
+	return &response, nil
+}
+
+func (s *UserService) GetByUsername(ctx context.Context, username string) (*UserResponseDTO, error) {
+	user, err := s.repo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.NewNotFoundError("User not found")
+		}
+		return nil, apperrors.NewInternalError("Failed to get user", err)
+	}
+
+	response := ToUserResponseDTO(user)
+	return &response, nil
+}
+
+func (s *UserService) GetAll(ctx context.Context, filtersDTO UserListFiltersDTO) ([]UserResponseDTO, error) {
+	if err := s.validator.Validate(filtersDTO); err != nil {
+		return nil, err
+	}
+
+	if filtersDTO.Limit == 0 {
+		filtersDTO.Limit = 20
+	}
+
+	users, err := s.repo.FindAll(ctx, domain.UserFilters{
+		Role:   filtersDTO.Role,
+		Active: filtersDTO.Active,
+		Limit:  filtersDTO.Limit,
+		Offset: filtersDTO.Offset,
+	})
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to get users", err)
+	}
+
+	return ToUserResponseDTOList(users), nil
+}
+
+// Update applies an administrator's changes to email, roles, active flag
+// and, optionally, resets the password. Changing the roles, resetting the
+// password or deactivating the account signs the user out, so access tokens
+// carrying the old roles stop working.
+func (s *UserService) Update(ctx context.Context, id string, dto UpdateUserDTO) (*UserResponseDTO, error) {
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.Email != nil && *dto.Email != user.Email {
+		exists, err := s.repo.ExistsByEmail(ctx, *dto.Email)
+		if err != nil {
+			return nil, apperrors.NewInternalError("Failed to check user existence", err)
+		}
+		if exists {
+			return nil, apperrors.NewAppError(409, "User with this email already exists", apperrors.ErrConflict)
+		}
+		if err := user.ChangeEmail(*dto.Email); err != nil {
+			return nil, apperrors.NewValidationError(err.Error(), nil)
+		}
+	}
+
+	signOut := false
+
+	if dto.Roles != nil {
+		previous := user.Roles
+		if err := user.SetRoles(dto.Roles); err != nil {
+			return nil, apperrors.NewValidationError(err.Error(), nil)
+		}
+		signOut = !sameRoles(previous, user.Roles)
+	}
+
+	if dto.Active != nil {
+		if *dto.Active {
+			user.Activate()
+		} else {
+			signOut = user.Active
+			user.Deactivate()
+		}
+	}
+
+	if dto.Password != nil {
+		if err := s.setPassword(user, *dto.Password); err != nil {
+			return nil, err
+		}
+		signOut = true
+	}
+
+	if err := s.save(ctx, user, signOut); err != nil {
+		return nil, err
+	}
+
+	response := ToUserResponseDTO(user)
+	return &response, nil
+}
+
+// Delete removes the user and, in the same transaction, revokes their access
+// tokens; their refresh tokens and API keys go with the row.
+func (s *UserService) Delete(ctx context.Context, id string) error {
+	if _, err := s.findUser(ctx, id); err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		if err := revokeSessions(ctx, repos, id, s.accessTTL, time.Now().UTC()); err != nil {
+			return err
+		}
+
+		if err := repos.Users().Delete(ctx, id); err != nil {
+			return apperrors.NewInternalError("Failed to delete user", err)
+		}
+		return nil
+	})
+}
+
+func (s *UserService) findUser(ctx context.Context, id string) (*domain.User, error) {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.NewNotFoundError("User not found")
+		}
+		return nil, apperrors.NewInternalError("Failed to get user", err)
+	}
+	return user, nil
+}
+
+// save stores the user and, when signOut is set, revokes their sessions and
+// access tokens in the same transaction.
+func (s *UserService) save(ctx context.Context, user *domain.User, signOut bool) error {
+	return s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		if err := repos.Users().Update(ctx, user); err != nil {
+			return apperrors.NewInternalError("Failed to update user", err)
+		}
+
+		if !signOut {
+			return nil
+		}
+		return revokeSessions(ctx, repos, user.ID, s.accessTTL, time.Now().UTC())
+	})
+}
+
+// sameRoles reports whether two role lists hold the same roles in any order.
+func sameRoles(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	held := make(map[string]bool, len(a))
+	for _, role := range a {
+		held[role] = true
+	}
+	for _, role := range b {
+		if !held[role] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *UserService) setPassword(user *domain.User, password string) error {
+	if err := domain.ValidatePassword(password); err != nil {
+		return apperrors.NewValidationError(err.Error(), nil)
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return apperrors.NewInternalError("Failed to hash password", err)
+	}
+
+	user.SetPasswordHash(hash)
+	return nil
+}
+
+func (s *UserService) dummyPasswordHash() string {
+	s.dummyOnce.Do(func() {
+		s.dummyHash, _ = s.hasher.Hash("dummy-password-0")
+	})
+	return s.dummyHash
+}
@@ -0,0 +1,274 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-architecture/internal/shared/auth"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/user/domain"
+)
+
+// TokenIssuer signs short-lived access tokens.
+type TokenIssuer interface {
+	Issue(principal auth.Principal) (*auth.IssuedToken, error)
+	TTL() time.Duration
+}
+
+// SessionService issues access/refresh token pairs, rotates refresh tokens
+// and maintains the access token revocation list.
+type SessionService struct {
+	users       *UserService
+	uow         UnitOfWork
+	revocations domain.RevokedTokenRepository
+	issuer      TokenIssuer
+	refreshTTL  time.Duration
+	validator   *Validator
+}
+
+func NewSessionService(users *UserService, uow UnitOfWork, revocations domain.RevokedTokenRepository, issuer TokenIssuer, refreshTTL time.Duration) *SessionService {
+	return &SessionService{
+		users:       users,
+		uow:         uow,
+		revocations: revocations,
+		issuer:      issuer,
+		refreshTTL:  refreshTTL,
+		validator:   NewValidator(),
+	}
+}
+
+func (s *SessionService) Login(ctx context.Context, dto LoginDTO) (*TokenPairDTO, error) {
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+
+	principal, err := s.users.VerifyCredentials(ctx, dto.Username, dto.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	token, raw, err := domain.NewRefreshToken(principal.UserID, "", s.refreshTTL)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to generate refresh token", err)
+	}
+
+	var pair *TokenPairDTO
+	err = s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		var err error
+		pair, err = s.issue(ctx, repos, *principal, token, raw)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. The presented token
+// is rotated out; presenting an already rotated token is treated as theft
+// and revokes every token of that session.
+func (s *SessionService) Refresh(ctx context.Context, dto RefreshTokenDTO) (*TokenPairDTO, error) {
+	if err := s.validator.Validate(dto); err != nil {
+		return nil, err
+	}
+
+	var pair *TokenPairDTO
+	reused := false
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		now := time.Now().UTC()
+
+		current, err := repos.RefreshTokens().FindByHash(ctx, domain.HashRefreshToken(dto.RefreshToken))
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return apperrors.NewUnauthorizedError(domain.ErrRefreshTokenInvalid.Error())
+			}
+			return apperrors.NewInternalError("Failed to get refresh token", err)
+		}
+
+		if current.WasRotated() {
+			reused = true
+			return s.revokeFamily(ctx, repos, current.FamilyID, now)
+		}
+
+		if !current.IsActive(now) {
+			return apperrors.NewUnauthorizedError(domain.ErrRefreshTokenInvalid.Error())
+		}
+
+		user, err := repos.Users().FindByID(ctx, current.UserID)
+		if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return apperrors.NewInternalError("Failed to get user", err)
+		}
+		if user == nil || !user.Active {
+			return apperrors.NewUnauthorizedError(domain.ErrRefreshTokenInvalid.Error())
+		}
+
+		next, raw, err := domain.NewRefreshToken(user.ID, current.FamilyID, s.refreshTTL)
+		if err != nil {
+			return apperrors.NewInternalError("Failed to generate refresh token", err)
+		}
+
+		if err := repos.RefreshTokens().MarkRotated(ctx, current.ID, next.ID, now); err != nil {
+			if errors.Is(err, domain.ErrRefreshTokenReused) {
+				// Lost a race against a concurrent refresh with the same token.
+				reused = true
+				return s.revokeFamily(ctx, repos, current.FamilyID, now)
+			}
+			return apperrors.NewInternalError("Failed to rotate refresh token", err)
+		}
+
+		pair, err = s.issue(ctx, repos, principalOf(user), next, raw)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reused {
+		return nil, apperrors.NewUnauthorizedError(domain.ErrRefreshTokenReused.Error())
+	}
+
+	return pair, nil
+}
+
+// Logout revokes the access token used for the request and, when given, the
+// session the refresh token belongs to.
+func (s *SessionService) Logout(ctx context.Context, userID, accessTokenID string, accessExpiresAt time.Time, dto LogoutDTO) error {
+	return s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		now := time.Now().UTC()
+
+		if err := revokeAccessToken(ctx, repos, accessTokenID, accessExpiresAt); err != nil {
+			return err
+		}
+
+		if dto.RefreshToken == "" {
+			return nil
+		}
+
+		token, err := repos.RefreshTokens().FindByHash(ctx, domain.HashRefreshToken(dto.RefreshToken))
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return nil
+			}
+			return apperrors.NewInternalError("Failed to get refresh token", err)
+		}
+
+		if token.UserID != userID {
+			return nil
+		}
+
+		return s.revokeFamily(ctx, repos, token.FamilyID, now)
+	})
+}
+
+// LogoutAll revokes every session of the user together with the access
+// tokens that may still be valid.
+func (s *SessionService) LogoutAll(ctx context.Context, userID, accessTokenID string, accessExpiresAt time.Time) error {
+	return s.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		now := time.Now().UTC()
+
+		if err := revokeAccessToken(ctx, repos, accessTokenID, accessExpiresAt); err != nil {
+			return err
+		}
+
+		return revokeSessions(ctx, repos, userID, s.issuer.TTL(), now)
+	})
+}
+
+// IsRevoked reports whether an access token ID is on the revocation list.
+func (s *SessionService) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return s.revocations.IsRevoked(ctx, tokenID)
+}
+
+func (s *SessionService) issue(ctx context.Context, repos Repositories, principal auth.Principal, token *domain.RefreshToken, raw string) (*TokenPairDTO, error) {
+	access, err := s.issuer.Issue(principal)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to generate token", err)
+	}
+
+	token.AccessTokenID = access.ID
+	token.AccessTokenExpiresAt = access.ExpiresAt
+
+	if err := repos.RefreshTokens().Create(ctx, token); err != nil {
+		return nil, apperrors.NewInternalError("Failed to store refresh token", err)
+	}
+
+	return &TokenPairDTO{
+		AccessToken:      access.Token,
+		TokenType:        "Bearer",
+		ExpiresAt:        access.ExpiresAt,
+		RefreshToken:     raw,
+		RefreshExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+func (s *SessionService) revokeFamily(ctx context.Context, repos Repositories, familyID string, now time.Time) error {
+	tokens, err := repos.RefreshTokens().ListByFamily(ctx, familyID, now.Add(-s.issuer.TTL()))
+	if err != nil {
+		return apperrors.NewInternalError("Failed to list session tokens", err)
+	}
+
+	if err := revokeAccessTokens(ctx, repos, tokens, now); err != nil {
+		return err
+	}
+
+	if err := repos.RefreshTokens().RevokeFamily(ctx, familyID, now); err != nil {
+		return apperrors.NewInternalError("Failed to revoke session", err)
+	}
+
+	return nil
+}
+
+// revokeSessions revokes every session of the user together with the access
+// tokens that may still be valid: every access token is issued alongside a
+// refresh token, so those created within accessTTL cover them all.
+func revokeSessions(ctx context.Context, repos Repositories, userID string, accessTTL time.Duration, now time.Time) error {
+	tokens, err := repos.RefreshTokens().ListByUser(ctx, userID, now.Add(-accessTTL))
+	if err != nil {
+		return apperrors.NewInternalError("Failed to list sessions", err)
+	}
+
+	if err := revokeAccessTokens(ctx, repos, tokens, now); err != nil {
+		return err
+	}
+
+	if err := repos.RefreshTokens().RevokeAllForUser(ctx, userID, now); err != nil {
+		return apperrors.NewInternalError("Failed to revoke sessions", err)
+	}
+
+	return nil
+}
+
+// revokeAccessTokens puts the access tokens issued alongside the given
+// refresh tokens on the revocation list if they have not expired yet.
+func revokeAccessTokens(ctx context.Context, repos Repositories, tokens []*domain.RefreshToken, now time.Time) error {
+	for _, token := range tokens {
+		if token.AccessTokenExpiresAt.After(now) {
+			if err := revokeAccessToken(ctx, repos, token.AccessTokenID, token.AccessTokenExpiresAt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func revokeAccessToken(ctx context.Context, repos Repositories, tokenID string, expiresAt time.Time) error {
+	if tokenID == "" {
+		return nil
+	}
+
+	if err := repos.RevokedTokens().Revoke(ctx, tokenID, expiresAt); err != nil {
+		return apperrors.NewInternalError("Failed to revoke access token", err)
+	}
+	return nil
+}
+
+func principalOf(user *domain.User) auth.Principal {
+	return auth.Principal{
+		UserID: user.ID,
+		Email:  user.Email,
+		Roles:  user.Roles,
+	}
+}
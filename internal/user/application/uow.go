@@ -0,0 +1,21 @@
+package application
+
+import (
+	"context"
+
+	"go-architecture/internal/user/domain"
+)
+
+// Repositories gives access to repositories bound to the same transaction.
+type Repositories interface {
+	Users() domain.UserRepository
+	RefreshTokens() domain.RefreshTokenRepository
+	RevokedTokens() domain.RevokedTokenRepository
+}
+
+// UnitOfWork runs a function inside a single database transaction. The
+// transaction is committed if fn returns nil and rolled back otherwise; the
+// error returned by fn is passed through unchanged.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"unicode"
+)
+
+var (
+	ErrWeakPassword       = errors.New("password must be 8 to 72 characters and contain a letter and a digit")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// PasswordHasher hashes and verifies passwords. Compare returns
+// ErrInvalidCredentials when the password does not match the hash.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hash, password string) error
+}
+
+// ValidatePassword enforces the password policy. The 72 byte upper bound is
+// the bcrypt input limit.
+func ValidatePassword(password string) error {
+	if len(password) < 8 || len(password) > 72 {
+		return ErrWeakPassword
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	if !hasLetter || !hasDigit {
+		return ErrWeakPassword
+	}
+	return nil
+}
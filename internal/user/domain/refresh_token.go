@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+// RefreshToken is a server-side session credential. Only the SHA-256 hash of
+// the opaque token is stored. Tokens issued from the same login share a
+// FamilyID so that reuse of a rotated token can revoke the whole session.
+type RefreshToken struct {
+	ID                   string
+	UserID               string
+	FamilyID             string
+	TokenHash            string
+	AccessTokenID        string
+	AccessTokenExpiresAt time.Time
+	ExpiresAt            time.Time
+	CreatedAt            time.Time
+	RevokedAt            *time.Time
+	ReplacedBy           string
+}
+
+// NewRefreshToken creates a token for userID and returns it together with the
+// opaque value to hand to the client. An empty familyID starts a new session.
+func NewRefreshToken(userID, familyID string, ttl time.Duration) (*RefreshToken, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+
+	if familyID == "" {
+		familyID = uuid.New().String()
+	}
+
+	now := time.Now().UTC()
+
+	return &RefreshToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: HashRefreshToken(raw),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, raw, nil
+}
+
+// HashRefreshToken returns the lookup hash stored for an opaque token.
+func HashRefreshToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// WasRotated reports whether the token was exchanged for a successor, which
+// makes any further use of it a replay.
+func (t *RefreshToken) WasRotated() bool {
+	return t.RevokedAt != nil && t.ReplacedBy != ""
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *User) error
+	FindByID(ctx context.Context, id string) (*User, error)
+	FindByUsername(ctx context.Context, username string) (*User, error)
+	FindAll(ctx context.Context, filters UserFilters) ([]*User, error)
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id string) error
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+}
+
+type UserFilters struct {
+	Role   string
+	Active *bool
+	Limit  int
+	Offset int
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*RefreshToken, error)
+	// MarkRotated revokes an active token and records its successor. It
+	// returns ErrRefreshTokenReused when the token is no longer active.
+	MarkRotated(ctx context.Context, id, replacedBy string, at time.Time) error
+	// ListByFamily and ListByUser return tokens created at or after since.
+	ListByFamily(ctx context.Context, familyID string, since time.Time) ([]*RefreshToken, error)
+	ListByUser(ctx context.Context, userID string, since time.Time) ([]*RefreshToken, error)
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+	RevokeAllForUser(ctx context.Context, userID string, at time.Time) error
+}
+
+// RevokedTokenRepository is the deny list of access token IDs (jti) that
+// must be rejected before they expire.
+type RevokedTokenRepository interface {
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	FindByID(ctx context.Context, id string) (*APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*APIKey, error)
+	FindAll(ctx context.Context, filters APIKeyFilters) ([]*APIKey, error)
+	// Update saves the expiry and revocation time.
+	Update(ctx context.Context, key *APIKey) error
+}
+
+type APIKeyFilters struct {
+	UserID string
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+var (
+	ErrInvalidUsername = errors.New("username must be 3 to 50 characters of letters, digits, '.', '_' or '-'")
+	ErrInvalidEmail    = errors.New("email address is not valid")
+	ErrInvalidRole     = errors.New("unknown role")
+	ErrNoRoles         = errors.New("user must have at least one role")
+	ErrUserInactive    = errors.New("user is inactive")
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{3,50}$`)
+
+var knownRoles = map[string]bool{
+	RoleAdmin: true,
+	RoleUser:  true,
+}
+
+type User struct {
+	ID           string
+	Username     string
+	Email        string
+	PasswordHash string
+	Roles        []string
+	Active       bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewUser creates an active user. The password must already be hashed.
+func NewUser(username, email, passwordHash string, roles []string) (*User, error) {
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err = normalizeRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &User{
+		ID:           uuid.New().String(),
+		Username:     username,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Roles:        roles,
+		Active:       true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+func (u *User) ChangeEmail(email string) error {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	u.Email = email
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+func (u *User) SetRoles(roles []string) error {
+	roles, err := normalizeRoles(roles)
+	if err != nil {
+		return err
+	}
+
+	u.Roles = roles
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+func (u *User) SetPasswordHash(hash string) {
+	u.PasswordHash = hash
+	u.UpdatedAt = time.Now()
+}
+
+func (u *User) Deactivate() {
+	u.Active = false
+	u.UpdatedAt = time.Now()
+}
+
+func (u *User) Activate() {
+	u.Active = true
+	u.UpdatedAt = time.Now()
+}
+
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func validateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return ErrInvalidUsername
+	}
+	return nil
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
+func normalizeRoles(roles []string) ([]string, error) {
+	seen := make(map[string]bool, len(roles))
+	result := make([]string, 0, len(roles))
+
+	for _, role := range roles {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if !knownRoles[role] {
+			return nil, ErrInvalidRole
+		}
+		if !seen[role] {
+			seen[role] = true
+			result = append(result, role)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, ErrNoRoles
+	}
+	return result, nil
+}
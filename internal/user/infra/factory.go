@@ -0,0 +1,51 @@
+package infra
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/shared/database"
+	"go-architecture/internal/user/application"
+	"go-architecture/internal/user/domain"
+	"go-architecture/internal/user/infra/mssql"
+	"go-architecture/internal/user/infra/postgres"
+	"go-architecture/internal/user/infra/sqlite"
+)
+
+// Repositories are the user persistence adapters for one backend.
+type Repositories struct {
+	Users         domain.UserRepository
+	RevokedTokens domain.RevokedTokenRepository
+	APIKeys       domain.APIKeyRepository
+	UnitOfWork    application.UnitOfWork
+}
+
+// NewRepositories builds the adapters matching the configured database
+// driver.
+func NewRepositories(driver string, db *sqlx.DB) (*Repositories, error) {
+	switch driver {
+	case database.DriverMSSQL:
+		return &Repositories{
+			Users:         mssql.NewUserRepository(db),
+			RevokedTokens: mssql.NewRevokedTokenRepository(db),
+			APIKeys:       mssql.NewAPIKeyRepository(db),
+			UnitOfWork:    mssql.NewUnitOfWork(db),
+		}, nil
+	case database.DriverPostgres:
+		return &Repositories{
+			Users:         postgres.NewUserRepository(db),
+			RevokedTokens: postgres.NewRevokedTokenRepository(db),
+			APIKeys:       postgres.NewAPIKeyRepository(db),
+			UnitOfWork:    postgres.NewUnitOfWork(db),
+		}, nil
+	case database.DriverSQLite:
+		return &Repositories{
+			Users:         sqlite.NewUserRepository(db),
+			RevokedTokens: sqlite.NewRevokedTokenRepository(db),
+			APIKeys:       sqlite.NewAPIKeyRepository(db),
+			UnitOfWork:    sqlite.NewUnitOfWork(db),
+		}, nil
+	default:
+		return nil, fmt.Errorf("user: no repositories for database driver %q", driver)
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go-architecture/internal/shared/logger"
+	"go-architecture/internal/user/application"
+)
+
+type APIKeyHandler struct {
+	service *application.APIKeyService
+	log     *logger.Logger
+}
+
+func NewAPIKeyHandler(service *application.APIKeyService, log *logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// Create issues a key. The response is the only time the raw key is shown.
+func (h *APIKeyHandler) Create(c *fiber.Ctx) error {
+	var dto application.CreateAPIKeyDTO
+
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	key, err := h.service.Create(c.UserContext(), dto)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": key,
+	})
+}
+
+func (h *APIKeyHandler) GetAll(c *fiber.Ctx) error {
+	var filters application.APIKeyListFiltersDTO
+
+	if err := c.QueryParser(&filters); err != nil {
+		h.log.Error("Failed to parse query parameters", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	keys, err := h.service.GetAll(c.UserContext(), filters)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  keys,
+		"count": len(keys),
+	})
+}
+
+// SetExpiry replaces the expiry of a key.
+func (h *APIKeyHandler) SetExpiry(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var dto application.UpdateAPIKeyDTO
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	key, err := h.service.SetExpiry(c.UserContext(), id, dto)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": key,
+	})
+}
+
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.service.Revoke(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go-architecture/internal/shared/logger"
+	"go-architecture/internal/shared/middleware"
+	"go-architecture/internal/user/application"
+)
+
+type AuthHandler struct {
+	sessions *application.SessionService
+	log      *logger.Logger
+}
+
+func NewAuthHandler(sessions *application.SessionService, log *logger.Logger) *AuthHandler {
+	return &AuthHandler{
+		sessions: sessions,
+		log:      log,
+	}
+}
+
+// Login exchanges valid credentials for an access token and a refresh token.
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var dto application.LoginDTO
+
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	pair, err := h.sessions.Login(c.UserContext(), dto)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(pair)
+}
+
+// Refresh rotates a refresh token and returns a new token pair.
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var dto application.RefreshTokenDTO
+
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	pair, err := h.sessions.Refresh(c.UserContext(), dto)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(pair)
+}
+
+// Logout revokes the current access token and, if sent, the session of the
+// given refresh token.
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var dto application.LogoutDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&dto); err != nil {
+			h.log.Error("Failed to parse request body", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	tokenID, expiresAt := currentToken(c)
+	if err := h.sessions.Logout(c.UserContext(), userID, tokenID, expiresAt, dto); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// LogoutAll revokes every session of the current user.
+func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	tokenID, expiresAt := currentToken(c)
+	if err := h.sessions.LogoutAll(c.UserContext(), userID, tokenID, expiresAt); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// currentToken returns the ID and expiry of the access token the principal
+// authenticated with.
+func currentToken(c *fiber.Ctx) (string, time.Time) {
+	principal, ok := middleware.Principal(c)
+	if !ok {
+		return "", time.Time{}
+	}
+	return principal.TokenID, principal.ExpiresAt
+}
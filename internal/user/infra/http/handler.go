@@ -0,0 +1,176 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go-architecture/internal/shared/errors"
+	"go-architecture/internal/shared/logger"
+	"go-architecture/internal/shared/middleware"
+	"go-architecture/internal/user/application"
+)
+
+type UserHandler struct {
+	service *application.UserService
+	log     *logger.Logger
+}
+
+func NewUserHandler(service *application.UserService, log *logger.Logger) *UserHandler {
+	return &UserHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+func (h *UserHandler) Register(c *fiber.Ctx) error {
+	var dto application.RegisterUserDTO
+
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	user, err := h.service.Register(c.UserContext(), dto)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+func (h *UserHandler) Me(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.service.GetByID(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var dto application.ChangePasswordDTO
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.service.ChangePassword(c.UserContext(), userID, dto); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func (h *UserHandler) Create(c *fiber.Ctx) error {
+	var dto application.CreateUserDTO
+
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	user, err := h.service.Create(c.UserContext(), dto)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+func (h *UserHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	user, err := h.service.GetByID(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+func (h *UserHandler) GetAll(c *fiber.Ctx) error {
+	var filters application.UserListFiltersDTO
+
+	if err := c.QueryParser(&filters); err != nil {
+		h.log.Error("Failed to parse query parameters", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	users, err := h.service.GetAll(c.UserContext(), filters)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  users,
+		"count": len(users),
+	})
+}
+
+func (h *UserHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var dto application.UpdateUserDTO
+	if err := c.BodyParser(&dto); err != nil {
+		h.log.Error("Failed to parse request body", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	user, err := h.service.Update(c.UserContext(), id, dto)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+func (h *UserHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if current, _ := currentUserID(c); current == id {
+		return errors.NewValidationError("You cannot delete your own account", nil)
+	}
+
+	if err := h.service.Delete(c.UserContext(), id); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// currentUserID returns the user ID of the authenticated principal.
+func currentUserID(c *fiber.Ctx) (string, error) {
+	principal, ok := middleware.Principal(c)
+	if !ok || principal.UserID == "" {
+		return "", errors.NewUnauthorizedError("User not authenticated")
+	}
+	return principal.UserID, nil
+}
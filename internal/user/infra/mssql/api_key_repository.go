@@ -0,0 +1,133 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/user/domain"
+	userquery "go-architecture/internal/user/infra/query"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type APIKeyRepository struct {
+	db database.Querier
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+type apiKeyModel struct {
+	ID        string         `db:"id"`
+	UserID    string         `db:"user_id"`
+	Name      string         `db:"name"`
+	Prefix    string         `db:"prefix"`
+	KeyHash   string         `db:"key_hash"`
+	Scopes    string         `db:"scopes"`
+	ExpiresAt sql.NullTime   `db:"expires_at"`
+	CreatedAt time.Time      `db:"created_at"`
+	CreatedBy sql.NullString `db:"created_by"`
+	RevokedAt sql.NullTime   `db:"revoked_at"`
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	query := `INSERT INTO api_keys (` + userquery.APIKeyColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	q := r.db.Rebind(query)
+	_, err := r.db.ExecContext(ctx, q,
+		key.ID,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		strings.Join(key.Scopes, ","),
+		key.ExpiresAt,
+		key.CreatedAt,
+		database.NullString(key.CreatedBy),
+		key.RevokedAt,
+	)
+	return err
+}
+
+func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	return r.findOne(ctx, `SELECT `+userquery.APIKeyColumns+` FROM api_keys WHERE id = ?`, id)
+}
+
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	return r.findOne(ctx, `SELECT `+userquery.APIKeyColumns+` FROM api_keys WHERE key_hash = ?`, hash)
+}
+
+func (r *APIKeyRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.APIKey, error) {
+	q := r.db.Rebind(query)
+
+	var m apiKeyModel
+	if err := r.db.GetContext(ctx, &m, q, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&m), nil
+}
+
+func (r *APIKeyRepository) FindAll(ctx context.Context, filters domain.APIKeyFilters) ([]*domain.APIKey, error) {
+	q, args := userquery.FindAPIKeys(database.MSSQL, filters)
+
+	var models []apiKeyModel
+	if err := r.db.SelectContext(ctx, &models, q, args...); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*domain.APIKey, 0, len(models))
+	for i := range models {
+		keys = append(keys, r.toDomain(&models[i]))
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Update(ctx context.Context, key *domain.APIKey) error {
+	q := r.db.Rebind(`UPDATE api_keys SET expires_at = ?, revoked_at = ? WHERE id = ?`)
+	res, err := r.db.ExecContext(ctx, q, key.ExpiresAt, key.RevokedAt, key.ID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) toDomain(m *apiKeyModel) *domain.APIKey {
+	key := &domain.APIKey{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Name:      m.Name,
+		Prefix:    m.Prefix,
+		KeyHash:   m.KeyHash,
+		Scopes:    strings.Split(m.Scopes, ","),
+		CreatedAt: m.CreatedAt,
+		CreatedBy: m.CreatedBy.String,
+	}
+	if m.ExpiresAt.Valid {
+		expiresAt := m.ExpiresAt.Time
+		key.ExpiresAt = &expiresAt
+	}
+	if m.RevokedAt.Valid {
+		revokedAt := m.RevokedAt.Time
+		key.RevokedAt = &revokedAt
+	}
+	return key
+}
@@ -0,0 +1,141 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/user/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RefreshTokenRepository struct {
+	db database.Querier
+}
+
+func NewRefreshTokenRepository(db *sqlx.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+type refreshTokenModel struct {
+	ID                   string         `db:"id"`
+	UserID               string         `db:"user_id"`
+	FamilyID             string         `db:"family_id"`
+	TokenHash            string         `db:"token_hash"`
+	AccessTokenID        string         `db:"access_token_id"`
+	AccessTokenExpiresAt time.Time      `db:"access_expires_at"`
+	ExpiresAt            time.Time      `db:"expires_at"`
+	CreatedAt            time.Time      `db:"created_at"`
+	RevokedAt            sql.NullTime   `db:"revoked_at"`
+	ReplacedBy           sql.NullString `db:"replaced_by"`
+}
+
+const refreshTokenColumns = "id, user_id, family_id, token_hash, access_token_id, access_expires_at, expires_at, created_at, revoked_at, replaced_by"
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, access_token_id, access_expires_at, expires_at, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	q := r.db.Rebind(query)
+	_, err := r.db.ExecContext(ctx, q,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.AccessTokenID,
+		token.AccessTokenExpiresAt,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	q := r.db.Rebind(`SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = ?`)
+
+	var m refreshTokenModel
+	if err := r.db.GetContext(ctx, &m, q, hash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return toRefreshTokenDomain(&m), nil
+}
+
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, id, replacedBy string, at time.Time) error {
+	q := r.db.Rebind(`UPDATE refresh_tokens SET revoked_at = ?, replaced_by = ? WHERE id = ? AND revoked_at IS NULL`)
+	res, err := r.db.ExecContext(ctx, q, at, replacedBy, id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) ListByFamily(ctx context.Context, familyID string, since time.Time) ([]*domain.RefreshToken, error) {
+	return r.list(ctx, `SELECT `+refreshTokenColumns+` FROM refresh_tokens WHERE family_id = ? AND created_at >= ?`, familyID, since)
+}
+
+func (r *RefreshTokenRepository) ListByUser(ctx context.Context, userID string, since time.Time) ([]*domain.RefreshToken, error) {
+	return r.list(ctx, `SELECT `+refreshTokenColumns+` FROM refresh_tokens WHERE user_id = ? AND created_at >= ?`, userID, since)
+}
+
+func (r *RefreshTokenRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.RefreshToken, error) {
+	q := r.db.Rebind(query)
+
+	var models []refreshTokenModel
+	if err := r.db.SelectContext(ctx, &models, q, args...); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*domain.RefreshToken, 0, len(models))
+	for i := range models {
+		tokens = append(tokens, toRefreshTokenDomain(&models[i]))
+	}
+	return tokens, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	q := r.db.Rebind(`UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`)
+	_, err := r.db.ExecContext(ctx, q, at, familyID)
+	return err
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string, at time.Time) error {
+	q := r.db.Rebind(`UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`)
+	_, err := r.db.ExecContext(ctx, q, at, userID)
+	return err
+}
+
+func toRefreshTokenDomain(m *refreshTokenModel) *domain.RefreshToken {
+	token := &domain.RefreshToken{
+		ID:                   m.ID,
+		UserID:               m.UserID,
+		FamilyID:             m.FamilyID,
+		TokenHash:            m.TokenHash,
+		AccessTokenID:        m.AccessTokenID,
+		AccessTokenExpiresAt: m.AccessTokenExpiresAt,
+		ExpiresAt:            m.ExpiresAt,
+		CreatedAt:            m.CreatedAt,
+	}
+	if m.RevokedAt.Valid {
+		revokedAt := m.RevokedAt.Time
+		token.RevokedAt = &revokedAt
+	}
+	if m.ReplacedBy.Valid {
+		token.ReplacedBy = m.ReplacedBy.String
+	}
+	return token
+}
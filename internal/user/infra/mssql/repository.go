@@ -0,0 +1,161 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/user/domain"
+	userquery "go-architecture/internal/user/infra/query"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type UserRepository struct {
+	db database.Querier
+}
+
+func NewUserRepository(db *sqlx.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+type userModel struct {
+	ID           string    `db:"id"`
+	Username     string    `db:"username"`
+	Email        string    `db:"email"`
+	PasswordHash string    `db:"password_hash"`
+	Roles        string    `db:"roles"`
+	Active       bool      `db:"active"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	query := `INSERT INTO users (` + userquery.UserColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	q := r.db.Rebind(query)
+	_, err := r.db.ExecContext(ctx, q,
+		user.ID,
+		user.Username,
+		user.Email,
+		user.PasswordHash,
+		strings.Join(user.Roles, ","),
+		user.Active,
+		user.CreatedAt,
+		user.UpdatedAt,
+	)
+	return err
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.findOne(ctx, `SELECT `+userquery.UserColumns+` FROM users WHERE id = ?`, id)
+}
+
+func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.findOne(ctx, `SELECT `+userquery.UserColumns+` FROM users WHERE username = ?`, username)
+}
+
+func (r *UserRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.User, error) {
+	q := r.db.Rebind(query)
+
+	var m userModel
+	if err := r.db.GetContext(ctx, &m, q, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return toDomain(&m), nil
+}
+
+func (r *UserRepository) FindAll(ctx context.Context, filters domain.UserFilters) ([]*domain.User, error) {
+	q, args := userquery.FindUsers(database.MSSQL, filters)
+
+	var models []userModel
+	if err := r.db.SelectContext(ctx, &models, q, args...); err != nil {
+		return nil, err
+	}
+
+	users := make([]*domain.User, 0, len(models))
+	for i := range models {
+		users = append(users, toDomain(&models[i]))
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	query := `UPDATE users SET email = ?, password_hash = ?, roles = ?, active = ?, updated_at = ? WHERE id = ?`
+	q := r.db.Rebind(query)
+	res, err := r.db.ExecContext(ctx, q,
+		user.Email,
+		user.PasswordHash,
+		strings.Join(user.Roles, ","),
+		user.Active,
+		user.UpdatedAt,
+		user.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	q := r.db.Rebind(`DELETE FROM users WHERE id = ?`)
+	res, err := r.db.ExecContext(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	return r.exists(ctx, `SELECT CASE WHEN EXISTS(SELECT 1 FROM users WHERE username = ?) THEN 1 ELSE 0 END`, username)
+}
+
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	return r.exists(ctx, `SELECT CASE WHEN EXISTS(SELECT 1 FROM users WHERE email = ?) THEN 1 ELSE 0 END`, email)
+}
+
+func (r *UserRepository) exists(ctx context.Context, query string, arg interface{}) (bool, error) {
+	q := r.db.Rebind(query)
+	var existsInt int
+	if err := r.db.GetContext(ctx, &existsInt, q, arg); err != nil {
+		return false, err
+	}
+	return existsInt == 1, nil
+}
+
+func toDomain(m *userModel) *domain.User {
+	return &domain.User{
+		ID:           m.ID,
+		Username:     m.Username,
+		Email:        m.Email,
+		PasswordHash: m.PasswordHash,
+		Roles:        strings.Split(m.Roles, ","),
+		Active:       m.Active,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
@@ -0,0 +1,36 @@
+package mssql
+
+import (
+	"context"
+	"time"
+
+	"go-architecture/internal/shared/database"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RevokedTokenRepository struct {
+	db database.Querier
+}
+
+func NewRevokedTokenRepository(db *sqlx.DB) *RevokedTokenRepository {
+	return &RevokedTokenRepository{db: db}
+}
+
+func (r *RevokedTokenRepository) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	query := `IF NOT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = ?)
+INSERT INTO revoked_tokens (jti, expires_at, revoked_at) VALUES (?, ?, ?)`
+
+	q := r.db.Rebind(query)
+	_, err := r.db.ExecContext(ctx, q, tokenID, tokenID, expiresAt, time.Now().UTC())
+	return err
+}
+
+func (r *RevokedTokenRepository) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	q := r.db.Rebind(`SELECT CASE WHEN EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = ?) THEN 1 ELSE 0 END`)
+	var existsInt int
+	if err := r.db.GetContext(ctx, &existsInt, q, tokenID); err != nil {
+		return false, err
+	}
+	return existsInt == 1, nil
+}
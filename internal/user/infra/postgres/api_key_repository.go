@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/user/domain"
+	userquery "go-architecture/internal/user/infra/query"
+)
+
+type APIKeyRepository struct {
+	db database.Querier
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+type apiKeyModel struct {
+	ID        string         `db:"id"`
+	UserID    string         `db:"user_id"`
+	Name      string         `db:"name"`
+	Prefix    string         `db:"prefix"`
+	KeyHash   string         `db:"key_hash"`
+	Scopes    string         `db:"scopes"`
+	ExpiresAt sql.NullTime   `db:"expires_at"`
+	CreatedAt time.Time      `db:"created_at"`
+	CreatedBy sql.NullString `db:"created_by"`
+	RevokedAt sql.NullTime   `db:"revoked_at"`
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	query := `
+		INSERT INTO api_keys (` + userquery.APIKeyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		key.ID,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		strings.Join(key.Scopes, ","),
+		key.ExpiresAt,
+		key.CreatedAt,
+		database.NullString(key.CreatedBy),
+		key.RevokedAt,
+	)
+
+	return err
+}
+
+func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	return r.findOne(ctx, `SELECT `+userquery.APIKeyColumns+` FROM api_keys WHERE id = $1`, id)
+}
+
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	return r.findOne(ctx, `SELECT `+userquery.APIKeyColumns+` FROM api_keys WHERE key_hash = $1`, hash)
+}
+
+func (r *APIKeyRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.APIKey, error) {
+	var model apiKeyModel
+	err := r.db.GetContext(ctx, &model, query, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+func (r *APIKeyRepository) FindAll(ctx context.Context, filters domain.APIKeyFilters) ([]*domain.APIKey, error) {
+	query, args := userquery.FindAPIKeys(database.Postgres, filters)
+
+	var models []apiKeyModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*domain.APIKey, len(models))
+	for i := range models {
+		keys[i] = r.toDomain(&models[i])
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Update(ctx context.Context, key *domain.APIKey) error {
+	query := `UPDATE api_keys SET expires_at = $1, revoked_at = $2 WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, key.ExpiresAt, key.RevokedAt, key.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return apperrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) toDomain(model *apiKeyModel) *domain.APIKey {
+	key := &domain.APIKey{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Name:      model.Name,
+		Prefix:    model.Prefix,
+		KeyHash:   model.KeyHash,
+		Scopes:    strings.Split(model.Scopes, ","),
+		CreatedAt: model.CreatedAt,
+		CreatedBy: model.CreatedBy.String,
+	}
+
+	if model.ExpiresAt.Valid {
+		expiresAt := model.ExpiresAt.Time
+		key.ExpiresAt = &expiresAt
+	}
+
+	if model.RevokedAt.Valid {
+		revokedAt := model.RevokedAt.Time
+		key.RevokedAt = &revokedAt
+	}
+
+	return key
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/user/domain"
+)
+
+type RefreshTokenRepository struct {
+	db database.Querier
+}
+
+func NewRefreshTokenRepository(db *sqlx.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+type refreshTokenModel struct {
+	ID                   string         `db:"id"`
+	UserID               string         `db:"user_id"`
+	FamilyID             string         `db:"family_id"`
+	TokenHash            string         `db:"token_hash"`
+	AccessTokenID        string         `db:"access_token_id"`
+	AccessTokenExpiresAt time.Time      `db:"access_expires_at"`
+	ExpiresAt            time.Time      `db:"expires_at"`
+	CreatedAt            time.Time      `db:"created_at"`
+	RevokedAt            sql.NullTime   `db:"revoked_at"`
+	ReplacedBy           sql.NullString `db:"replaced_by"`
+}
+
+const refreshTokenColumns = "id, user_id, family_id, token_hash, access_token_id, access_expires_at, expires_at, created_at, revoked_at, replaced_by"
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, access_token_id, access_expires_at, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.AccessTokenID,
+		token.AccessTokenExpiresAt,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1`
+
+	var model refreshTokenModel
+	err := r.db.GetContext(ctx, &model, query, hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, id, replacedBy string, at time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1, replaced_by = $2 WHERE id = $3 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, at, replacedBy, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) ListByFamily(ctx context.Context, familyID string, since time.Time) ([]*domain.RefreshToken, error) {
+	return r.list(ctx, `SELECT `+refreshTokenColumns+` FROM refresh_tokens WHERE family_id = $1 AND created_at >= $2`, familyID, since)
+}
+
+func (r *RefreshTokenRepository) ListByUser(ctx context.Context, userID string, since time.Time) ([]*domain.RefreshToken, error) {
+	return r.list(ctx, `SELECT `+refreshTokenColumns+` FROM refresh_tokens WHERE user_id = $1 AND created_at >= $2`, userID, since)
+}
+
+func (r *RefreshTokenRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.RefreshToken, error) {
+	var models []refreshTokenModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*domain.RefreshToken, len(models))
+	for i := range models {
+		tokens[i] = r.toDomain(&models[i])
+	}
+
+	return tokens, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`, at, familyID)
+	return err
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`, at, userID)
+	return err
+}
+
+func (r *RefreshTokenRepository) toDomain(model *refreshTokenModel) *domain.RefreshToken {
+	token := &domain.RefreshToken{
+		ID:                   model.ID,
+		UserID:               model.UserID,
+		FamilyID:             model.FamilyID,
+		TokenHash:            model.TokenHash,
+		AccessTokenID:        model.AccessTokenID,
+		AccessTokenExpiresAt: model.AccessTokenExpiresAt,
+		ExpiresAt:            model.ExpiresAt,
+		CreatedAt:            model.CreatedAt,
+	}
+
+	if model.RevokedAt.Valid {
+		revokedAt := model.RevokedAt.Time
+		token.RevokedAt = &revokedAt
+	}
+
+	if model.ReplacedBy.Valid {
+		token.ReplacedBy = model.ReplacedBy.String
+	}
+
+	return token
+}
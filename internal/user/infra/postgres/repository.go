@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/shared/database"
+	apperrors "go-architecture/internal/shared/errors"
+	"go-architecture/internal/user/domain"
+	userquery "go-architecture/internal/user/infra/query"
+)
+
+type UserRepository struct {
+	db database.Querier
+}
+
+func NewUserRepository(db *sqlx.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+type userModel struct {
+	ID           string    `db:"id"`
+	Username     string    `db:"username"`
+	Email        string    `db:"email"`
+	PasswordHash string    `db:"password_hash"`
+	Roles        string    `db:"roles"`
+	Active       bool      `db:"active"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	query := `
+		INSERT INTO users (` + userquery.UserColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		user.ID,
+		user.Username,
+		user.Email,
+		user.PasswordHash,
+		strings.Join(user.Roles, ","),
+		user.Active,
+		user.CreatedAt,
+		user.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.findOne(ctx, `SELECT `+userquery.UserColumns+` FROM users WHERE id = $1`, id)
+}
+
+func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.findOne(ctx, `SELECT `+userquery.UserColumns+` FROM users WHERE username = $1`, username)
+}
+
+func (r *UserRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.User, error) {
+	var model userModel
+	err := r.db.GetContext(ctx, &model, query, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+func (r *UserRepository) FindAll(ctx context.Context, filters domain.UserFilters) ([]*domain.User, error) {
+	query, args := userquery.FindUsers(database.Postgres, filters)
+
+	var models []userModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, err
+	}
+
+	users := make([]*domain.User, len(models))
+	for i := range models {
+		users[i] = r.toDomain(&models[i])
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	query := `
+		UPDATE users
+		SET email = $1, password_hash = $2, roles = $3, active = $4, updated_at = $5
+		WHERE id = $6
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		user.Email,
+		user.PasswordHash,
+		strings.Join(user.Roles, ","),
+		user.Active,
+		user.UpdatedAt,
+		user.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return apperrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return apperrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`, username)
+	return exists, err
+}
+
+func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`, email)
+	return exists, err
+}
+
+func (r *UserRepository) toDomain(model *userModel) *domain.User {
+	return &domain.User{
+		ID:           model.ID,
+		Username:     model.Username,
+		Email:        model.Email,
+		PasswordHash: model.PasswordHash,
+		Roles:        strings.Split(model.Roles, ","),
+		Active:       model.Active,
+		CreatedAt:    model.CreatedAt,
+		UpdatedAt:    model.UpdatedAt,
+	}
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/shared/database"
+)
+
+type RevokedTokenRepository struct {
+	db database.Querier
+}
+
+func NewRevokedTokenRepository(db *sqlx.DB) *RevokedTokenRepository {
+	return &RevokedTokenRepository{db: db}
+}
+
+func (r *RevokedTokenRepository) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, expires_at, revoked_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, tokenID, expiresAt, time.Now().UTC())
+	return err
+}
+
+func (r *RevokedTokenRepository) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)`, tokenID)
+	return exists, err
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"go-architecture/internal/shared/database"
+	"go-architecture/internal/user/application"
+	"go-architecture/internal/user/domain"
+)
+
+type UnitOfWork struct {
+	db *sqlx.DB
+}
+
+func NewUnitOfWork(db *sqlx.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos application.Repositories) error) error {
+	return database.RunInTx(ctx, u.db, func(tx *sqlx.Tx) error {
+		return fn(ctx, &txRepositories{tx: tx})
+	})
+}
+
+// txRepositories hands out repositories bound to one transaction.
+type txRepositories struct {
+	tx *sqlx.Tx
+}
+
+func (r *txRepositories) Users() domain.UserRepository {
+	return &UserRepository{db: r.tx}
+}
+
+func (r *txRepositories) RefreshTokens() domain.RefreshTokenRepository {
+	return &RefreshTokenRepository{db: r.tx}
+}
+
+func (r *txRepositories) RevokedTokens() domain.RevokedTokenRepository {
+	return &RevokedTokenRepository{db: r.tx}
+}
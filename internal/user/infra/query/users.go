@@ -0,0 +1,41 @@
+// Package query builds the user SQL shared by every database backend, so
+// that filters are written once and rendered for each dialect.
+package query
+
+import (
+	"go-architecture/internal/shared/database"
+	"go-architecture/internal/user/domain"
+)
+
+// UserColumns are the columns scanned into a user row model.
+const UserColumns = "id, username, email, password_hash, roles, active, created_at, updated_at"
+
+// APIKeyColumns are the columns scanned into an API key row model.
+const APIKeyColumns = "id, user_id, name, prefix, key_hash, scopes, expires_at, created_at, created_by, revoked_at"
+
+// FindUsers selects the users matching filters, newest first. Roles are
+// stored comma-separated, so a role matches when ",role," occurs in
+// ",roles,".
+func FindUsers(d database.Dialect, filters domain.UserFilters) (string, []interface{}) {
+	q := d.Select(UserColumns, "users")
+
+	if filters.Role != "" {
+		q.Where(d.Concat("','", "roles", "','")+" LIKE ?", "%,"+filters.Role+",%")
+	}
+	if filters.Active != nil {
+		q.Where("active = ?", *filters.Active)
+	}
+
+	return q.OrderBy("created_at DESC").Paginate(filters.Limit, filters.Offset).Build()
+}
+
+// FindAPIKeys selects the API keys matching filters, newest first.
+func FindAPIKeys(d database.Dialect, filters domain.APIKeyFilters) (string, []interface{}) {
+	q := d.Select(APIKeyColumns, "api_keys")
+
+	if filters.UserID != "" {
+		q.Where("user_id = ?", filters.UserID)
+	}
+
+	return q.OrderBy("created_at DESC").Paginate(filters.Limit, filters.Offset).Build()
+}
@@ -0,0 +1,11 @@
+// Package migrations embeds the SQL migration scripts so the binary can
+// apply them without the source tree.
+package migrations
+
+import "embed"
+
+// FS holds one directory per database driver (mssql, postgres, sqlite), each
+// with numbered NNN_name.up.sql and NNN_name.down.sql scripts.
+//
+//go:embed mssql postgres sqlite
+var FS embed.FS
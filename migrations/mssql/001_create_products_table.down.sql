@@ -0,0 +1,5 @@
+IF OBJECT_ID('dbo.trg_products_updated_at', 'TR') IS NOT NULL
+    DROP TRIGGER dbo.trg_products_updated_at;
+
+IF OBJECT_ID(N'dbo.products', N'U') IS NOT NULL
+    DROP TABLE [dbo].[products];
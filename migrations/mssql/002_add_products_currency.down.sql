@@ -0,0 +1,5 @@
+IF COL_LENGTH('dbo.products', 'currency') IS NOT NULL
+BEGIN
+    ALTER TABLE [dbo].[products] DROP CONSTRAINT df_products_currency;
+    ALTER TABLE [dbo].[products] DROP COLUMN [currency];
+END
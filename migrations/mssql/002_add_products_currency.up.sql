@@ -0,0 +1,6 @@
+-- Migration: Store the ISO-4217 currency alongside the exact DECIMAL price
+IF COL_LENGTH('dbo.products', 'currency') IS NULL
+BEGIN
+    ALTER TABLE [dbo].[products]
+        ADD [currency] NVARCHAR(3) NOT NULL CONSTRAINT df_products_currency DEFAULT 'USD';
+END
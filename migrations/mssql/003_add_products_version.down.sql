@@ -0,0 +1,5 @@
+IF COL_LENGTH('dbo.products', 'version') IS NOT NULL
+BEGIN
+    ALTER TABLE [dbo].[products] DROP CONSTRAINT df_products_version;
+    ALTER TABLE [dbo].[products] DROP COLUMN [version];
+END
@@ -0,0 +1,6 @@
+-- Migration: Version column for optimistic concurrency control
+IF COL_LENGTH('dbo.products', 'version') IS NULL
+BEGIN
+    ALTER TABLE [dbo].[products]
+        ADD [version] BIGINT NOT NULL CONSTRAINT df_products_version DEFAULT 1;
+END
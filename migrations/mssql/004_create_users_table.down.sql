@@ -0,0 +1,2 @@
+IF OBJECT_ID(N'dbo.users', N'U') IS NOT NULL
+    DROP TABLE [dbo].[users];
@@ -0,0 +1,16 @@
+-- Migration: Create users table for SQL Server
+IF NOT EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[users]') AND type in (N'U'))
+BEGIN
+    CREATE TABLE [dbo].[users] (
+        [id] NVARCHAR(36) NOT NULL PRIMARY KEY,
+        [username] NVARCHAR(50) NOT NULL UNIQUE,
+        [email] NVARCHAR(255) NOT NULL UNIQUE,
+        [password_hash] NVARCHAR(255) NOT NULL,
+        [roles] NVARCHAR(255) NOT NULL DEFAULT 'user',
+        [active] BIT NOT NULL DEFAULT 1,
+        [created_at] DATETIME2 NOT NULL DEFAULT (SYSUTCDATETIME()),
+        [updated_at] DATETIME2 NOT NULL DEFAULT (SYSUTCDATETIME())
+    );
+
+    CREATE INDEX idx_users_created_at ON [dbo].[users]([created_at]);
+END
@@ -0,0 +1,5 @@
+IF OBJECT_ID(N'dbo.revoked_tokens', N'U') IS NOT NULL
+    DROP TABLE [dbo].[revoked_tokens];
+
+IF OBJECT_ID(N'dbo.refresh_tokens', N'U') IS NOT NULL
+    DROP TABLE [dbo].[refresh_tokens];
@@ -0,0 +1,31 @@
+-- Migration: Server-side refresh tokens (hashed) grouped into session families
+IF NOT EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[refresh_tokens]') AND type in (N'U'))
+BEGIN
+    CREATE TABLE [dbo].[refresh_tokens] (
+        [id] NVARCHAR(36) NOT NULL PRIMARY KEY,
+        [user_id] NVARCHAR(36) NOT NULL REFERENCES [dbo].[users]([id]) ON DELETE CASCADE,
+        [family_id] NVARCHAR(36) NOT NULL,
+        [token_hash] NVARCHAR(64) NOT NULL UNIQUE,
+        [access_token_id] NVARCHAR(36) NOT NULL,
+        [access_expires_at] DATETIME2 NOT NULL,
+        [expires_at] DATETIME2 NOT NULL,
+        [created_at] DATETIME2 NOT NULL DEFAULT (SYSUTCDATETIME()),
+        [revoked_at] DATETIME2 NULL,
+        [replaced_by] NVARCHAR(36) NULL
+    );
+
+    CREATE INDEX idx_refresh_tokens_family ON [dbo].[refresh_tokens]([family_id]);
+    CREATE INDEX idx_refresh_tokens_user ON [dbo].[refresh_tokens]([user_id]);
+END
+
+-- Revoked access token IDs (jti), kept until the token would have expired
+IF NOT EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[revoked_tokens]') AND type in (N'U'))
+BEGIN
+    CREATE TABLE [dbo].[revoked_tokens] (
+        [jti] NVARCHAR(36) NOT NULL PRIMARY KEY,
+        [expires_at] DATETIME2 NOT NULL,
+        [revoked_at] DATETIME2 NOT NULL DEFAULT (SYSUTCDATETIME())
+    );
+
+    CREATE INDEX idx_revoked_tokens_expires_at ON [dbo].[revoked_tokens]([expires_at]);
+END
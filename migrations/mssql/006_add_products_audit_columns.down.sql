@@ -0,0 +1,5 @@
+IF COL_LENGTH('dbo.products', 'updated_by') IS NOT NULL
+    ALTER TABLE [dbo].[products] DROP COLUMN [updated_by];
+
+IF COL_LENGTH('dbo.products', 'created_by') IS NOT NULL
+    ALTER TABLE [dbo].[products] DROP COLUMN [created_by];
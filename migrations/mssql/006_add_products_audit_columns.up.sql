@@ -0,0 +1,10 @@
+-- Migration: Users who created and last changed each product
+IF COL_LENGTH('dbo.products', 'created_by') IS NULL
+BEGIN
+    ALTER TABLE [dbo].[products] ADD [created_by] NVARCHAR(36) NULL;
+END
+
+IF COL_LENGTH('dbo.products', 'updated_by') IS NULL
+BEGIN
+    ALTER TABLE [dbo].[products] ADD [updated_by] NVARCHAR(36) NULL;
+END
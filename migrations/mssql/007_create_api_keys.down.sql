@@ -0,0 +1,2 @@
+IF OBJECT_ID(N'dbo.api_keys', N'U') IS NOT NULL
+    DROP TABLE [dbo].[api_keys];
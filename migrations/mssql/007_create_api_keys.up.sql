@@ -0,0 +1,18 @@
+-- Migration: API keys for machine clients (hashed), acting for their owning user
+IF NOT EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[api_keys]') AND type in (N'U'))
+BEGIN
+    CREATE TABLE [dbo].[api_keys] (
+        [id] NVARCHAR(36) NOT NULL PRIMARY KEY,
+        [user_id] NVARCHAR(36) NOT NULL REFERENCES [dbo].[users]([id]) ON DELETE CASCADE,
+        [name] NVARCHAR(100) NOT NULL,
+        [prefix] NVARCHAR(16) NOT NULL,
+        [key_hash] NVARCHAR(64) NOT NULL UNIQUE,
+        [scopes] NVARCHAR(1000) NOT NULL,
+        [expires_at] DATETIME2 NULL,
+        [created_at] DATETIME2 NOT NULL DEFAULT (SYSUTCDATETIME()),
+        [created_by] NVARCHAR(36) NULL,
+        [revoked_at] DATETIME2 NULL
+    );
+
+    CREATE INDEX idx_api_keys_user ON [dbo].[api_keys]([user_id]);
+END
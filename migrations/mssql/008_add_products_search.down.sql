@@ -0,0 +1,9 @@
+-- migrate:no-transaction
+IF EXISTS (SELECT * FROM sys.fulltext_indexes WHERE object_id = OBJECT_ID(N'dbo.products'))
+    EXEC('DROP FULLTEXT INDEX ON dbo.products');
+
+IF EXISTS (SELECT * FROM sys.fulltext_catalogs WHERE name = N'ftc_products')
+    EXEC('DROP FULLTEXT CATALOG ftc_products');
+
+IF EXISTS (SELECT * FROM sys.indexes WHERE name = N'ux_products_id' AND object_id = OBJECT_ID(N'dbo.products'))
+    DROP INDEX ux_products_id ON [dbo].[products];
@@ -0,0 +1,16 @@
+-- migrate:no-transaction
+-- Migration: Full-text index over product names and descriptions. Skipped when
+-- the Full-Text Search feature is not installed; searches then fall back to LIKE.
+IF FULLTEXTSERVICEPROPERTY('IsFullTextInstalled') = 1
+BEGIN
+    IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = N'ux_products_id' AND object_id = OBJECT_ID(N'dbo.products'))
+        CREATE UNIQUE INDEX ux_products_id ON [dbo].[products]([id]);
+
+    IF NOT EXISTS (SELECT * FROM sys.fulltext_catalogs WHERE name = N'ftc_products')
+        EXEC('CREATE FULLTEXT CATALOG ftc_products');
+
+    IF NOT EXISTS (SELECT * FROM sys.fulltext_indexes WHERE object_id = OBJECT_ID(N'dbo.products'))
+        EXEC('CREATE FULLTEXT INDEX ON dbo.products ([name], [description])
+            KEY INDEX ux_products_id ON ftc_products
+            WITH CHANGE_TRACKING AUTO');
+END
@@ -0,0 +1,6 @@
+IF COL_LENGTH('dbo.products', 'status') IS NOT NULL
+BEGIN
+    DROP INDEX IF EXISTS idx_products_status ON [dbo].[products];
+    ALTER TABLE [dbo].[products] DROP CONSTRAINT IF EXISTS chk_products_status, df_products_status;
+    ALTER TABLE [dbo].[products] DROP COLUMN [status];
+END
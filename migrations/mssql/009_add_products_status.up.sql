@@ -0,0 +1,10 @@
+-- Migration: Product lifecycle status; existing products keep their active flag
+IF COL_LENGTH('dbo.products', 'status') IS NULL
+BEGIN
+    ALTER TABLE [dbo].[products] ADD [status] NVARCHAR(20) NOT NULL
+        CONSTRAINT df_products_status DEFAULT 'active'
+        CONSTRAINT chk_products_status CHECK ([status] IN ('draft', 'active', 'discontinued', 'archived'));
+
+    EXEC('UPDATE [dbo].[products] SET [status] = ''discontinued'' WHERE [active] = 0');
+    EXEC('CREATE INDEX idx_products_status ON [dbo].[products]([status])');
+END
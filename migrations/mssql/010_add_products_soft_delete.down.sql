@@ -0,0 +1,5 @@
+IF COL_LENGTH('dbo.products', 'deleted_at') IS NOT NULL
+BEGIN
+    DROP INDEX IF EXISTS idx_products_deleted_at ON [dbo].[products];
+    ALTER TABLE [dbo].[products] DROP COLUMN [deleted_by], [deleted_at];
+END
@@ -0,0 +1,7 @@
+-- Migration: Deleted products are kept, marked with who deleted them and when
+IF COL_LENGTH('dbo.products', 'deleted_at') IS NULL
+BEGIN
+    ALTER TABLE [dbo].[products] ADD [deleted_at] DATETIME2 NULL, [deleted_by] NVARCHAR(36) NULL;
+
+    EXEC('CREATE INDEX idx_products_deleted_at ON [dbo].[products]([deleted_at])');
+END
@@ -0,0 +1,2 @@
+IF OBJECT_ID(N'[dbo].[stock_movements]', N'U') IS NOT NULL
+    DROP TABLE [dbo].[stock_movements];
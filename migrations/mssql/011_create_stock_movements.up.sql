@@ -0,0 +1,21 @@
+-- Migration: Ledger of stock movements; existing stock is recorded as an opening movement
+IF NOT EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[stock_movements]') AND type in (N'U'))
+BEGIN
+    CREATE TABLE [dbo].[stock_movements] (
+        [id] NVARCHAR(36) NOT NULL PRIMARY KEY,
+        [product_id] NVARCHAR(36) NOT NULL REFERENCES [dbo].[products]([id]) ON DELETE CASCADE,
+        [quantity] INT NOT NULL,
+        [reason] NVARCHAR(20) NOT NULL
+            CONSTRAINT chk_stock_movements_reason CHECK ([reason] IN ('initial', 'sale', 'restock', 'damage', 'correction')),
+        [stock_after] INT NOT NULL,
+        [note] NVARCHAR(200) NULL,
+        [created_at] DATETIME2 NOT NULL DEFAULT (SYSUTCDATETIME()),
+        [created_by] NVARCHAR(36) NULL
+    );
+
+    CREATE INDEX idx_stock_movements_product ON [dbo].[stock_movements]([product_id], [created_at]);
+
+    -- Opening movements reuse the product ID, which is unique per product
+    INSERT INTO [dbo].[stock_movements] ([id], [product_id], [quantity], [reason], [stock_after], [created_at], [created_by])
+    SELECT [id], [id], [stock], 'initial', [stock], [created_at], [created_by] FROM [dbo].[products];
+END
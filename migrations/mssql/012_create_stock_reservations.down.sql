@@ -0,0 +1,8 @@
+IF OBJECT_ID(N'[dbo].[stock_reservations]', N'U') IS NOT NULL
+    DROP TABLE [dbo].[stock_reservations];
+
+IF COL_LENGTH('dbo.products', 'reserved') IS NOT NULL
+BEGIN
+    ALTER TABLE [dbo].[products] DROP CONSTRAINT IF EXISTS chk_products_reserved, df_products_reserved;
+    ALTER TABLE [dbo].[products] DROP COLUMN [reserved];
+END
@@ -0,0 +1,26 @@
+-- Migration: Stock held by pending reservations, which never exceeds the stock
+IF COL_LENGTH('dbo.products', 'reserved') IS NULL
+BEGIN
+    ALTER TABLE [dbo].[products] ADD [reserved] INT NOT NULL
+        CONSTRAINT df_products_reserved DEFAULT 0;
+
+    EXEC('ALTER TABLE [dbo].[products] ADD CONSTRAINT chk_products_reserved CHECK ([reserved] >= 0 AND [reserved] <= [stock])');
+END
+
+IF NOT EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[stock_reservations]') AND type in (N'U'))
+BEGIN
+    CREATE TABLE [dbo].[stock_reservations] (
+        [id] NVARCHAR(36) NOT NULL PRIMARY KEY,
+        [product_id] NVARCHAR(36) NOT NULL REFERENCES [dbo].[products]([id]) ON DELETE CASCADE,
+        [quantity] INT NOT NULL CONSTRAINT chk_stock_reservations_quantity CHECK ([quantity] > 0),
+        [status] NVARCHAR(20) NOT NULL
+            CONSTRAINT chk_stock_reservations_status CHECK ([status] IN ('pending', 'confirmed', 'released', 'expired')),
+        [expires_at] DATETIME2 NOT NULL,
+        [created_at] DATETIME2 NOT NULL DEFAULT (SYSUTCDATETIME()),
+        [created_by] NVARCHAR(36) NULL,
+        [resolved_at] DATETIME2 NULL
+    );
+
+    CREATE INDEX idx_stock_reservations_product ON [dbo].[stock_reservations]([product_id]);
+    CREATE INDEX idx_stock_reservations_expiry ON [dbo].[stock_reservations]([status], [expires_at]);
+END
@@ -0,0 +1,6 @@
+-- migrate:no-transaction
+-- The catalog was created with the accent sensitivity of the database
+-- collation; only an accent-insensitive one differs from the up migration.
+IF EXISTS (SELECT * FROM sys.fulltext_catalogs WHERE name = N'ftc_products')
+    AND CAST(DATABASEPROPERTYEX(DB_NAME(), 'Collation') AS NVARCHAR(128)) LIKE N'%[_]AI%'
+    EXEC('ALTER FULLTEXT CATALOG ftc_products REBUILD WITH ACCENT_SENSITIVITY = OFF');
@@ -0,0 +1,5 @@
+-- migrate:no-transaction
+-- Migration: Full-text search matches accents as written, like the other
+-- backends, whatever the database collation: "cafe" does not find "café".
+IF EXISTS (SELECT * FROM sys.fulltext_catalogs WHERE name = N'ftc_products')
+    EXEC('ALTER FULLTEXT CATALOG ftc_products REBUILD WITH ACCENT_SENSITIVITY = ON');
@@ -12,10 +12,10 @@ CREATE TABLE IF NOT EXISTS products (
 );
 
 -- Create index for category searches
-CREATE INDEX idx_products_category ON products(category);
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
 
 -- Create index for active products
-CREATE INDEX idx_products_active ON products(active);
+CREATE INDEX IF NOT EXISTS idx_products_active ON products(active);
 
 -- Create index for created_at for ordering
-CREATE INDEX idx_products_created_at ON products(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_products_created_at ON products(created_at DESC);
@@ -0,0 +1,2 @@
+-- Store the ISO-4217 currency alongside the exact DECIMAL price
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';
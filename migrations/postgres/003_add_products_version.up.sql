@@ -0,0 +1,2 @@
+-- Version column for optimistic concurrency control
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
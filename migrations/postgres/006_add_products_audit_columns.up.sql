@@ -0,0 +1,3 @@
+-- Users who created and last changed each product
+ALTER TABLE products ADD COLUMN IF NOT EXISTS created_by VARCHAR(36);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS updated_by VARCHAR(36);
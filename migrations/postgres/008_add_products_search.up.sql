@@ -0,0 +1,9 @@
+-- Full-text search over product names (weight A) and descriptions (weight B).
+-- The 'simple' configuration neither stems nor drops stop words, so searches
+-- match word prefixes like the other backends.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search ON products USING GIN (search_vector);
@@ -0,0 +1,7 @@
+-- Product lifecycle status; existing products keep their active flag
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('draft', 'active', 'discontinued', 'archived'));
+
+UPDATE products SET status = 'discontinued' WHERE active = FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_products_status ON products(status);
@@ -0,0 +1,5 @@
+-- Deleted products are kept, marked with who deleted them and when
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_by VARCHAR(36);
+
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products(deleted_at);
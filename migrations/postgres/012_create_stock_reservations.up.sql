@@ -0,0 +1,17 @@
+-- Stock held by pending reservations, which never exceeds the stock
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reserved INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE products ADD CONSTRAINT chk_products_reserved CHECK (reserved >= 0 AND reserved <= stock);
+
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id VARCHAR(36) PRIMARY KEY,
+    product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'confirmed', 'released', 'expired')),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(36),
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_product ON stock_reservations(product_id);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_expiry ON stock_reservations(status, expires_at);
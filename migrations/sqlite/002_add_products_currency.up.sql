@@ -0,0 +1,2 @@
+-- Store the ISO-4217 currency alongside the price
+ALTER TABLE products ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
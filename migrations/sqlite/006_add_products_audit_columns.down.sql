@@ -0,0 +1,2 @@
+ALTER TABLE products DROP COLUMN updated_by;
+ALTER TABLE products DROP COLUMN created_by;
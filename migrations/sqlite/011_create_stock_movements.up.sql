@@ -0,0 +1,17 @@
+-- Ledger of stock movements; existing stock is recorded as an opening movement
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id VARCHAR(36) PRIMARY KEY,
+    product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('initial', 'sale', 'restock', 'damage', 'correction')),
+    stock_after INTEGER NOT NULL,
+    note VARCHAR(200),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(36)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product ON stock_movements(product_id, created_at);
+
+-- Opening movements reuse the product ID, which is unique per product
+INSERT INTO stock_movements (id, product_id, quantity, reason, stock_after, created_at, created_by)
+SELECT id, id, stock, 'initial', stock, created_at, created_by FROM products;
@@ -0,0 +1,30 @@
+-- Restore the accent-insensitive search index of migration 008.
+DROP TRIGGER IF EXISTS products_fts_delete;
+DROP TRIGGER IF EXISTS products_fts_update;
+DROP TRIGGER IF EXISTS products_fts_insert;
+DROP TABLE IF EXISTS products_fts;
+
+CREATE VIRTUAL TABLE products_fts USING fts5(
+    id UNINDEXED,
+    name,
+    description,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO products_fts (id, name, description)
+SELECT id, name, description FROM products;
+
+CREATE TRIGGER products_fts_insert AFTER INSERT ON products
+BEGIN
+    INSERT INTO products_fts (id, name, description) VALUES (new.id, new.name, new.description);
+END;
+
+CREATE TRIGGER products_fts_update AFTER UPDATE OF name, description ON products
+BEGIN
+    UPDATE products_fts SET name = new.name, description = new.description WHERE id = old.id;
+END;
+
+CREATE TRIGGER products_fts_delete AFTER DELETE ON products
+BEGIN
+    DELETE FROM products_fts WHERE id = old.id;
+END;